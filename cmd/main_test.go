@@ -75,3 +75,22 @@ func prepareTemplateConfig(t *testing.T) string {
 
 	return cfgPath
 }
+
+func TestMainUsesExitErrorCode(t *testing.T) {
+	cfgPath := prepareTemplateConfig(t)
+
+	origArgs := os.Args
+	defer func() { os.Args = origArgs }()
+	os.Args = []string{"mcp-templates", "lint", "missing", "--config", cfgPath}
+
+	origExit := exitFunc
+	defer func() { exitFunc = origExit }()
+
+	var exitCode int
+	exitFunc = func(code int) {
+		exitCode = code
+	}
+
+	main()
+	require.Equal(t, 2, exitCode)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/caarlos0/env/v10"
@@ -23,6 +24,7 @@ const (
 // Config define os parâmetros de configuração globais carregados via arquivo YAML e variáveis de ambiente.
 type Config struct {
 	TemplatesPath string              `yaml:"templates_path" env:"TEMPLATES_PATH"`
+	CacheDir      string              `yaml:"cache_dir" env:"TEMPLATES_CACHE_DIR"`
 	Logging       LoggingConfig       `yaml:"logging"`
 	Observability ObservabilityConfig `yaml:"observability"`
 	Rendering     RenderingConfig     `yaml:"rendering"`
@@ -49,12 +51,16 @@ type ObservabilityConfig struct {
 type RenderingConfig struct {
 	OperationTimeout time.Duration `yaml:"operation_timeout" env:"RENDER_TIMEOUT"`
 	MaxRetryAttempts int           `yaml:"max_retry_attempts" env:"RENDER_MAX_RETRY_ATTEMPTS"`
+	// Concurrency limita os arquivos renderizados em paralelo; zero usa o
+	// número de CPUs.
+	Concurrency int `yaml:"concurrency" env:"RENDER_CONCURRENCY"`
 }
 
 // Load carrega a configuração padrão, opcionalmente mesclando com um arquivo YAML e variáveis de ambiente.
 func Load(path string) (*Config, error) {
 	cfg := &Config{
 		TemplatesPath: defaultTemplatesPath,
+		CacheDir:      defaultCacheDir(),
 		Logging: LoggingConfig{
 			Level: defaultLogLevel,
 		},
@@ -89,6 +95,14 @@ func Load(path string) (*Config, error) {
 	return cfg, nil
 }
 
+// defaultCacheDir guarda templates remotos (git e arquivos compactados) no cache do usuário.
+func defaultCacheDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "mcp-templates")
+	}
+	return filepath.Join(os.TempDir(), "mcp-templates")
+}
+
 func mergeFile(cfg *Config, path string) error {
 	content, err := os.ReadFile(path)
 	if err != nil {
@@ -109,6 +123,9 @@ func validate(cfg *Config) error {
 	if cfg.TemplatesPath == "" {
 		return errors.New("templates_path must not be empty")
 	}
+	if cfg.CacheDir == "" {
+		return errors.New("cache_dir must not be empty")
+	}
 	if cfg.Observability.MetricsAddr == "" {
 		return errors.New("observability.metrics_address must not be empty")
 	}
@@ -121,5 +138,8 @@ func validate(cfg *Config) error {
 	if cfg.Rendering.MaxRetryAttempts <= 0 {
 		return errors.New("rendering.max_retry_attempts must be positive")
 	}
+	if cfg.Rendering.Concurrency < 0 {
+		return errors.New("rendering.concurrency must not be negative")
+	}
 	return nil
 }
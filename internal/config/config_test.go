@@ -26,12 +26,14 @@ rendering:
 `), 0o644))
 
 	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("RENDER_CONCURRENCY", "4")
 
 	cfg, err := Load(path)
 	require.NoError(t, err)
 	require.Equal(t, "custom", cfg.TemplatesPath)
 	require.Equal(t, "debug", cfg.Logging.Level)
 	require.Equal(t, 5, cfg.Rendering.MaxRetryAttempts)
+	require.Equal(t, 4, cfg.Rendering.Concurrency)
 	require.Equal(t, defaultOperationTimeout, cfg.Rendering.OperationTimeout)
 }
 
@@ -55,3 +57,10 @@ rendering:
 	_, err := Load(path)
 	require.Error(t, err)
 }
+
+func TestLoadNegativeConcurrency(t *testing.T) {
+	t.Setenv("RENDER_CONCURRENCY", "-1")
+
+	_, err := Load("")
+	require.ErrorContains(t, err, "rendering.concurrency")
+}
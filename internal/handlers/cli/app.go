@@ -24,12 +24,16 @@ type App struct {
 }
 
 // NewApp carrega a configuração e prepara dependências centrais.
-func NewApp(cfg *config.Config) *App {
+func NewApp(cfg *config.Config) (*App, error) {
 	loggerProvider := log.New(cfg.Logging.Level, cfg.Logging.Pretty)
 	logger := loggerProvider.Logger()
 
+	repository, err := templateservice.NewRepositoryFromURI(cfg.TemplatesPath, cfg.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+
 	obsSvc := observability.New(cfg.Observability, logger)
-	repository := templateservice.NewDefaultRepository(cfg.TemplatesPath)
 	templateSvc := templateservice.New(cfg.Rendering, logger, obsSvc.Registry(), repository)
 
 	return &App{
@@ -38,7 +42,7 @@ func NewApp(cfg *config.Config) *App {
 		logger:          logger,
 		obs:             obsSvc,
 		templateService: templateSvc,
-	}
+	}, nil
 }
 
 // Context retorna um contexto preparado com tratamento de sinais.
@@ -20,7 +20,8 @@ func TestAppContextCancel(t *testing.T) {
 			MetricsNS:     "test",
 		},
 	}
-	app := NewApp(cfg)
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
 
 	ctx, cancel := app.Context()
 	cancel()
@@ -43,7 +44,8 @@ func TestAppLogger(t *testing.T) {
 			MetricsNS:     "test",
 		},
 	}
-	app := NewApp(cfg)
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
 
 	logger := app.Logger()
 	require.NotNil(t, &logger)
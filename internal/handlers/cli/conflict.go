@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+)
+
+// conflictPrompt responde --on-conflict=prompt perguntando arquivo a arquivo.
+// As respostas em maiúscula valem para todos os conflitos seguintes.
+type conflictPrompt struct {
+	in  *bufio.Reader
+	out io.Writer
+	all templateservice.ConflictMode
+}
+
+func newConflictPrompt(in io.Reader, out io.Writer) *conflictPrompt {
+	return &conflictPrompt{in: bufio.NewReader(in), out: out}
+}
+
+var conflictAnswers = map[string]templateservice.ConflictMode{
+	"m": templateservice.ConflictSkip,
+	"s": templateservice.ConflictOverwrite,
+	"b": templateservice.ConflictBackup,
+}
+
+// resolve implementa templateservice.ConflictResolver. Enter mantém o arquivo
+// local; "a" cancela a renderização sem alterar a saída.
+func (p *conflictPrompt) resolve(path string) (templateservice.ConflictMode, error) {
+	if p.all != "" {
+		return p.all, nil
+	}
+	for {
+		fmt.Fprintf(p.out, "%s difere do template: [m]anter, [s]obrescrever, [b]ackup (M/S/B para todos), [a]bortar [m]: ", path)
+		line, err := p.in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+			if errors.Is(err, io.EOF) {
+				return "", fmt.Errorf("conflito em %s: entrada encerrada", path)
+			}
+			return "", fmt.Errorf("ler resposta: %w", err)
+		}
+
+		switch line {
+		case "":
+			return templateservice.ConflictSkip, nil
+		case "a", "A":
+			return "", errCancelled
+		}
+		if mode, ok := conflictAnswers[line]; ok {
+			return mode, nil
+		}
+		if mode, ok := conflictAnswers[strings.ToLower(line)]; ok {
+			p.all = mode
+			return mode, nil
+		}
+		fmt.Fprintln(p.out, "Responda m, s, b, M, S, B ou a.")
+	}
+}
+
+var conflictLabels = map[templateservice.ConflictMode]string{
+	templateservice.ConflictSkip:      "mantido",
+	templateservice.ConflictOverwrite: "sobrescrito",
+	templateservice.ConflictBackup:    "backup",
+}
+
+// printConflicts lista o que foi feito com os arquivos que diferiam do template.
+func printConflicts(out io.Writer, conflicts []templateservice.ResolvedConflict) {
+	if len(conflicts) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "%d arquivo(s) existente(s) diferiam do template:\n", len(conflicts))
+	for _, conflict := range conflicts {
+		line := fmt.Sprintf("  %-12s %s", conflictLabels[conflict.Action], conflict.Path)
+		if conflict.Backup != "" {
+			line += " -> " + conflict.Backup
+		}
+		fmt.Fprintln(out, line)
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+)
+
+func TestConflictPrompt(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	prompt := newConflictPrompt(strings.NewReader("x\n\nb\nS\n"), &out)
+
+	answers := []templateservice.ConflictMode{}
+	for _, path := range []string{"a.txt", "b.txt", "c.txt", "d.txt", "e.txt"} {
+		mode, err := prompt.resolve(path)
+		require.NoError(t, err)
+		answers = append(answers, mode)
+	}
+	require.Equal(t, []templateservice.ConflictMode{
+		templateservice.ConflictSkip,
+		templateservice.ConflictBackup,
+		templateservice.ConflictOverwrite,
+		templateservice.ConflictOverwrite,
+		templateservice.ConflictOverwrite,
+	}, answers)
+	require.Contains(t, out.String(), "Responda m, s, b, M, S, B ou a.")
+	require.Equal(t, 4, strings.Count(out.String(), "difere do template"), "S vale para os arquivos seguintes")
+
+	_, err := newConflictPrompt(strings.NewReader("a\n"), &out).resolve("a.txt")
+	require.ErrorIs(t, err, errCancelled)
+
+	_, err = newConflictPrompt(strings.NewReader(""), &out).resolve("a.txt")
+	require.EqualError(t, err, "conflito em a.txt: entrada encerrada")
+}
+
+func TestPrintConflicts(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	printConflicts(&out, []templateservice.ResolvedConflict{
+		{Path: "README.md", Action: templateservice.ConflictSkip},
+		{Path: "go.mod", Action: templateservice.ConflictBackup, Backup: "go.mod.orig"},
+	})
+	require.Equal(t, "2 arquivo(s) existente(s) diferiam do template:\n"+
+		"  mantido      README.md\n"+
+		"  backup       go.mod -> go.mod.orig\n", out.String())
+}
+
+func TestExecuteRenderCommandOnConflict(t *testing.T) {
+	temp := setupTemplateDir(t)
+
+	outputDir := filepath.Join(temp.root, "repo")
+	require.NoError(t, os.MkdirAll(outputDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "README.md"), []byte("local"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "main.go"), []byte("package main\n"), 0o644))
+	base := []string{"render", "--config", temp.configPath, "--template", "demo", "--output", outputDir}
+
+	err := ExecuteWithArgs(context.Background(), append(base, "--on-conflict", "merge"))
+	require.ErrorContains(t, err, `--on-conflict inválido "merge"`)
+	err = ExecuteWithArgs(context.Background(), append(base, "--on-conflict", "skip", "--overwrite"))
+	require.ErrorContains(t, err, "mutuamente exclusivos")
+	err = ExecuteWithArgs(context.Background(), append(base, "--on-conflict", "skip", "--backup-dir"))
+	require.ErrorContains(t, err, "--backup-dir requer --on-conflict=backup ou prompt")
+
+	require.NoError(t, ExecuteWithArgs(context.Background(), append(base, "--on-conflict", "prompt", "--dry-run")))
+	require.NoError(t, ExecuteWithArgs(context.Background(), append(base, "--on-conflict", "backup")))
+	data, err := os.ReadFile(filepath.Join(outputDir, "README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "sample", string(data))
+	data, err = os.ReadFile(filepath.Join(outputDir, "README.md.orig"))
+	require.NoError(t, err)
+	require.Equal(t, "local", string(data))
+	require.FileExists(t, filepath.Join(outputDir, "main.go"))
+}
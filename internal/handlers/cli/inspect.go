@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+func inspectCommand() *cobra.Command {
+	var (
+		profile string
+		asJSON  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "inspect <template>",
+		Short: "Descreve um template: variáveis, hooks, requisitos e árvore gerada",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app := MustApp(cmd)
+
+			report, err := app.TemplateService().Inspect(cmd.Context(), args[0], profile)
+			if err != nil {
+				return err
+			}
+			return printInspect(cmd.OutOrStdout(), report, asJSON)
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Perfil de valores usado nos defaults e na árvore gerada")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Formato JSON")
+	return cmd
+}
+
+var fileKindLabels = map[string]string{
+	"template": "template",
+	"copy":     "cópia",
+	"binary":   "binário",
+}
+
+func printInspect(out io.Writer, report *templateservice.InspectReport, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("serializar template: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(out, "%s (%s) %s\n", report.DisplayName, report.Name, report.Version)
+	if report.Description != "" {
+		fmt.Fprintln(out, report.Description)
+	}
+	if len(report.Tags) > 0 {
+		fmt.Fprintf(out, "Tags: %s\n", strings.Join(report.Tags, ", "))
+	}
+	if report.Extends != "" {
+		fmt.Fprintf(out, "Herda de: %s\n", report.Extends)
+	}
+	if len(report.Profiles) > 0 {
+		fmt.Fprintf(out, "Perfis: %s\n", strings.Join(report.Profiles, ", "))
+	}
+	if report.RuntimeFuncs {
+		fmt.Fprintln(out, "Usa now e uuid (runtime_funcs)")
+	}
+
+	if err := printInspectVariables(out, report.Variables); err != nil {
+		return err
+	}
+	if len(report.Computed) > 0 {
+		fmt.Fprintln(out, "\nVariáveis computadas:")
+		for _, computed := range report.Computed {
+			line := fmt.Sprintf("  %s = %s", computed.Key, computed.Expression)
+			if computed.Value != nil {
+				line += " -> " + valuespkg.String(computed.Value)
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+
+	printInspectHooks(out, report)
+
+	fmt.Fprintln(out)
+	if report.FilesError != "" {
+		fmt.Fprintf(out, "Arquivos: não foi possível renderizar com os defaults: %s\n", report.FilesError)
+		return nil
+	}
+	summary := report.Summary
+	fmt.Fprintf(out, "Arquivos: %d (%d template, %d cópia, %d binário) em %d diretório(s), %s\n",
+		summary.Files, summary.Templates, summary.Copied, summary.Binary, summary.Dirs, formatBytes(summary.TotalSize))
+	printFileTree(out, report.Files)
+	return nil
+}
+
+func printInspectVariables(out io.Writer, variables []templateservice.InspectedVariable) error {
+	if len(variables) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out, "\nVariáveis:")
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  NOME\tTIPO\tOBRIGATÓRIA\tPADRÃO\tDESCRIÇÃO")
+	for _, variable := range variables {
+		required := "não"
+		if variable.Required {
+			required = "sim"
+		}
+		kind := string(variable.Type)
+		if len(variable.Choices) > 0 {
+			kind += " (" + strings.Join(variable.Choices, "|") + ")"
+		}
+		if variable.Secret {
+			kind += ", secreta"
+		}
+		def := "-"
+		if variable.Default != nil {
+			def = valuespkg.String(variable.Default)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", variable.Key, kind, required, def, variable.Description)
+	}
+	return tw.Flush()
+}
+
+func printInspectHooks(out io.Writer, report *templateservice.InspectReport) {
+	phases := []struct {
+		name  string
+		steps []models.HookStep
+	}{
+		{"pre_render", report.Hooks.PreRender},
+		{"post_render", report.Hooks.PostRender},
+	}
+	if len(report.Hooks.PreRender)+len(report.Hooks.PostRender) > 0 {
+		fmt.Fprintln(out, "\nHooks:")
+		for _, phase := range phases {
+			for _, step := range phase.steps {
+				line := fmt.Sprintf("  %s: %s", phase.name, step.Action)
+				if step.Name != "" {
+					line += fmt.Sprintf(" (%s)", step.Name)
+				}
+				if step.When != "" {
+					line += ", quando " + step.When
+				}
+				if step.Optional {
+					line += ", opcional"
+				}
+				fmt.Fprintln(out, line)
+			}
+		}
+	}
+
+	if len(report.Requirements) == 0 && !report.AllowHooks {
+		return
+	}
+	fmt.Fprintln(out, "\nRequisitos:")
+	for _, requirement := range report.Requirements {
+		line := fmt.Sprintf("  %s (hook %s", requirement.Tool, requirement.Hook)
+		if requirement.Optional {
+			line += ", opcional"
+		}
+		fmt.Fprintln(out, line+")")
+	}
+	if report.AllowHooks {
+		fmt.Fprintln(out, "  --allow-hooks (hooks command)")
+	}
+}
+
+// printFileTree imprime os caminhos, já ordenados, como árvore indentada.
+func printFileTree(out io.Writer, files []templateservice.InspectedFile) {
+	var open []string
+	for _, file := range files {
+		segments := strings.Split(file.Path, "/")
+		dirs := segments[:len(segments)-1]
+
+		common := 0
+		for common < len(open) && common < len(dirs) && open[common] == dirs[common] {
+			common++
+		}
+		for depth := common; depth < len(dirs); depth++ {
+			fmt.Fprintf(out, "  %s%s/\n", strings.Repeat("  ", depth), dirs[depth])
+		}
+		open = dirs
+
+		fmt.Fprintf(out, "  %s%s  [%s, %s]\n", strings.Repeat("  ", len(dirs)), segments[len(segments)-1],
+			fileKindLabels[string(file.Kind)], formatBytes(file.Size))
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+)
+
+func TestPrintInspectHuman(t *testing.T) {
+	t.Parallel()
+
+	report := &templateservice.InspectReport{
+		Name:        "demo",
+		DisplayName: "Demo",
+		Version:     "1.0.0",
+		Tags:        []string{"go", "grpc"},
+		Variables: []templateservice.InspectedVariable{
+			{Key: "service", Type: models.VariableTypeString, Required: true, Default: "payments"},
+			{Key: "token", Type: models.VariableTypeString, Secret: true},
+		},
+		Computed:     []templateservice.InspectedComputed{{Key: "slug", Expression: "{{ kebab .service }}", Value: "payments"}},
+		Hooks:        models.Hooks{PostRender: []models.HookStep{{Name: "tidy", Action: models.HookGoModTidy, Optional: true}}},
+		Requirements: []templateservice.Requirement{{Tool: "go", Hook: "tidy", Optional: true}},
+		Files: []templateservice.InspectedFile{
+			{Path: "README.md", Kind: pkgtemplate.FileRendered, Size: 10},
+			{Path: "cmd/api/main.go", Kind: pkgtemplate.FileRendered, Size: 20},
+			{Path: "cmd/worker/main.go", Kind: pkgtemplate.FileRendered, Size: 20},
+			{Path: "static/logo.png", Kind: pkgtemplate.FileBinary, Size: 2048},
+		},
+		Summary: templateservice.FileSummary{Files: 4, Dirs: 4, Templates: 3, Binary: 1, TotalSize: 2098},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printInspect(&out, report, false))
+	text := out.String()
+	require.Contains(t, text, "Demo (demo) 1.0.0\nTags: go, grpc\n")
+	require.Regexp(t, `service\s+string\s+sim\s+payments`, text)
+	require.Regexp(t, `token\s+string, secreta\s+não\s+-`, text)
+	require.Contains(t, text, "  slug = {{ kebab .service }} -> payments\n")
+	require.Contains(t, text, "  post_render: go_mod_tidy (tidy), opcional\n")
+	require.Contains(t, text, "  go (hook tidy, opcional)\n")
+	require.Contains(t, text, "Arquivos: 4 (3 template, 0 cópia, 1 binário) em 4 diretório(s), 2.0 KiB\n")
+	require.Contains(t, text, "  README.md  [template, 10 B]\n"+
+		"  cmd/\n"+
+		"    api/\n"+
+		"      main.go  [template, 20 B]\n"+
+		"    worker/\n"+
+		"      main.go  [template, 20 B]\n"+
+		"  static/\n"+
+		"    logo.png  [binário, 2.0 KiB]\n")
+
+	out.Reset()
+	report.FilesError = "validate values: service is required"
+	require.NoError(t, printInspect(&out, report, false))
+	require.Contains(t, out.String(), "não foi possível renderizar com os defaults: validate values")
+}
+
+func TestPrintInspectJSONHookTimeout(t *testing.T) {
+	t.Parallel()
+
+	report := &templateservice.InspectReport{
+		Name: "demo",
+		Hooks: models.Hooks{PostRender: []models.HookStep{
+			{Name: "tidy", Action: models.HookGoModTidy, Timeout: 30 * time.Second},
+			{Name: "seed", Action: models.HookCommand, Timeout: 2 * time.Minute},
+			{Name: "fmt", Action: models.HookGofmt},
+		}},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printInspect(&out, report, true))
+	require.Contains(t, out.String(), `"timeout": "30s"`)
+	require.Contains(t, out.String(), `"timeout": "2m"`)
+	require.Equal(t, 2, bytes.Count(out.Bytes(), []byte(`"timeout"`)))
+
+	var decoded templateservice.InspectReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, report.Hooks, decoded.Hooks)
+}
+
+func TestExecuteInspectCommandJSON(t *testing.T) {
+	temp := setupTemplateDir(t)
+
+	out, restore := captureStdout(t)
+	require.NoError(t, ExecuteWithArgs(context.Background(), []string{"inspect", "demo", "--config", temp.configPath, "--json"}))
+	restore()
+
+	data, err := io.ReadAll(out)
+	require.NoError(t, err)
+	_ = out.Close()
+
+	var report templateservice.InspectReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.Equal(t, "demo", report.Name)
+	require.Equal(t, "sample", report.Variables[0].Default)
+	require.Equal(t, []templateservice.InspectedFile{{Path: "README.md", Kind: pkgtemplate.FileRendered, Size: int64(len("sample"))}}, report.Files)
+}
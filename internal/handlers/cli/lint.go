@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+)
+
+// Códigos de saída do lint.
+const (
+	lintExitIssues = 1
+	lintExitFailed = 2
+)
+
+func lintCommand() *cobra.Command {
+	var (
+		strict bool
+		asJSON bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint [template]",
+		Short: "Valida templates sem renderizá-los",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			app := MustApp(cmd)
+			ctx := cmd.Context()
+
+			names := args
+			if len(names) == 0 {
+				result, err := app.TemplateService().List(ctx, templateservice.ListOptions{})
+				if err != nil {
+					return &ExitError{Code: lintExitFailed, Err: err}
+				}
+				for _, tmpl := range result.Templates {
+					names = append(names, tmpl.Name)
+				}
+				// Templates omitidos da listagem são analisados mesmo assim, para
+				// que o lint falhe apontando o template.yaml inválido.
+				for _, skipped := range result.Skipped {
+					names = append(names, skipped.Name)
+				}
+			}
+
+			reports := make([]*templateservice.LintReport, 0, len(names))
+			for _, name := range names {
+				report, err := app.TemplateService().Lint(ctx, name)
+				if err != nil {
+					return &ExitError{Code: lintExitFailed, Err: fmt.Errorf("lint %s: %w", name, err)}
+				}
+				reports = append(reports, report)
+			}
+
+			if err := printLint(cmd.OutOrStdout(), reports, asJSON); err != nil {
+				return err
+			}
+			return lintResult(reports, strict)
+		},
+	}
+
+	cmd.Flags().BoolVar(&strict, "strict", false, "Trata avisos como erros")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Formato JSON")
+	return cmd
+}
+
+func printLint(out io.Writer, reports []*templateservice.LintReport, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("serializar resultado: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	for _, report := range reports {
+		for _, issue := range report.Issues {
+			location := report.Template
+			if issue.Path != "" {
+				location += "/" + issue.Path
+			}
+			if issue.Line > 0 {
+				location += fmt.Sprintf(":%d", issue.Line)
+			}
+			fmt.Fprintf(out, "%s: %s: %s [%s]\n", location, issue.Severity, issue.Message, issue.Code)
+		}
+	}
+	errs, warnings := lintTotals(reports)
+	fmt.Fprintf(out, "%d erro(s), %d aviso(s) em %d template(s)\n", errs, warnings, len(reports))
+	return nil
+}
+
+// lintResult converte o total de problemas no código de saída do comando.
+func lintResult(reports []*templateservice.LintReport, strict bool) error {
+	errs, warnings := lintTotals(reports)
+	if errs > 0 || (strict && warnings > 0) {
+		return &ExitError{Code: lintExitIssues, Err: fmt.Errorf("lint encontrou %d erro(s) e %d aviso(s)", errs, warnings)}
+	}
+	return nil
+}
+
+func lintTotals(reports []*templateservice.LintReport) (int, int) {
+	errs, warnings := 0, 0
+	for _, report := range reports {
+		errs += report.Count(templateservice.LintError)
+		warnings += report.Count(templateservice.LintWarning)
+	}
+	return errs, warnings
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+)
+
+func TestPrintLintAndExitCode(t *testing.T) {
+	t.Parallel()
+
+	reports := []*templateservice.LintReport{
+		{Template: "clean"},
+		{Template: "demo", Issues: []templateservice.LintIssue{
+			{Path: "main.go.tmpl", Line: 3, Severity: templateservice.LintError, Code: templateservice.LintUnknownFunction, Message: `função "shout" não definida`},
+			{Severity: templateservice.LintWarning, Code: templateservice.LintUnusedVariable, Message: `variável "x" declarada e nunca usada`},
+		}},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printLint(&out, reports, false))
+	require.Contains(t, out.String(), "demo/main.go.tmpl:3: error: função \"shout\" não definida [unknown-function]\n")
+	require.Contains(t, out.String(), "demo: warning: variável \"x\" declarada e nunca usada [unused-variable]\n")
+	require.Contains(t, out.String(), "1 erro(s), 1 aviso(s) em 2 template(s)")
+
+	var exitErr *ExitError
+	require.True(t, errors.As(lintResult(reports, false), &exitErr))
+	require.Equal(t, lintExitIssues, exitErr.Code)
+
+	warningsOnly := []*templateservice.LintReport{{Template: "demo", Issues: reports[1].Issues[1:]}}
+	require.NoError(t, lintResult(warningsOnly, false))
+	require.Error(t, lintResult(warningsOnly, true))
+}
@@ -3,12 +3,30 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
 )
 
+// listOutputs são os formatos aceitos em --output.
+var listOutputs = []string{"table", "wide", "json", "yaml", "names"}
+
 func listCommand() *cobra.Command {
-	var asJSON bool
+	var (
+		tags        []string
+		search      string
+		output      string
+		sortBy      string
+		allVersions bool
+		asJSON      bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -17,30 +35,119 @@ func listCommand() *cobra.Command {
 			app := MustApp(cmd)
 			ctx := cmd.Context()
 
-			templates, err := app.TemplateService().List(ctx)
+			format, err := listOutput(output, asJSON, cmd.Flags().Changed("output"))
 			if err != nil {
 				return err
 			}
 
-			if asJSON {
-				data, err := json.MarshalIndent(templates, "", "  ")
-				if err != nil {
-					return fmt.Errorf("serializar templates: %w", err)
-				}
-				fmt.Fprintln(cmd.OutOrStdout(), string(data))
-				return nil
+			result, err := app.TemplateService().List(ctx, templateservice.ListOptions{
+				Tags:        tagGroups(tags),
+				Search:      search,
+				Sort:        templateservice.ListSort(sortBy),
+				AllVersions: allVersions,
+			})
+			if err != nil {
+				return err
 			}
 
-			out := cmd.OutOrStdout()
-			fmt.Fprintf(out, "%-20s %-20s %s\n", "NAME", "VERSION", "DESCRIPTION")
-			for _, tmpl := range templates {
-				fmt.Fprintf(out, "%-20s %-20s %s\n", tmpl.Name, tmpl.Version, tmpl.Description)
+			for _, skipped := range result.Skipped {
+				fmt.Fprintf(cmd.ErrOrStderr(), "aviso: template %s ignorado: %s\n", skipped.Name, skipped.Reason)
 			}
-			return nil
+			return printList(cmd.OutOrStdout(), result.Templates, format, allVersions)
 		},
 	}
 
-	cmd.Flags().BoolVar(&asJSON, "json", false, "Formato JSON")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "Filtrar por tag; repita para exigir todas e separe por vírgula para aceitar qualquer uma (go,grpc)")
+	cmd.Flags().StringVar(&search, "search", "", "Busca aproximada em nome, display_name e descrição")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Formato: "+strings.Join(listOutputs, ", "))
+	cmd.Flags().StringVar(&sortBy, "sort", "", "Ordenação: name, version ou relevance (padrão: relevance com --search, name sem)")
+	cmd.Flags().BoolVar(&allVersions, "all-versions", false, "Listar cada versão disponível de cada template")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Formato JSON (equivale a --output json)")
 	return cmd
 }
 
+func listOutput(output string, asJSON, outputSet bool) (string, error) {
+	if asJSON {
+		if outputSet && output != "json" {
+			return "", fmt.Errorf("--json não pode ser combinado com --output %s", output)
+		}
+		return "json", nil
+	}
+	for _, format := range listOutputs {
+		if output == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("--output inválido %q: use %s", output, strings.Join(listOutputs, ", "))
+}
+
+// tagGroups converte os valores de --tag em grupos: cada ocorrência da flag é um
+// grupo e as tags separadas por vírgula dentro dela são alternativas.
+func tagGroups(values []string) [][]string {
+	var groups [][]string
+	for _, value := range values {
+		var group []string
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				group = append(group, tag)
+			}
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// printList imprime os templates no formato escolhido. Com allVersions, names
+// imprime referências <nome>@<versão>, aceitas por --template.
+func printList(out io.Writer, templates []models.TemplateMetadata, format string, allVersions bool) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(templates, "", "  ")
+		if err != nil {
+			return fmt.Errorf("serializar templates: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(templates)
+		if err != nil {
+			return fmt.Errorf("serializar templates: %w", err)
+		}
+		_, err = out.Write(data)
+		return err
+	case "names":
+		for _, tmpl := range templates {
+			if allVersions && tmpl.Version != "" {
+				fmt.Fprintf(out, "%s@%s\n", tmpl.Name, tmpl.Version)
+				continue
+			}
+			fmt.Fprintln(out, tmpl.Name)
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if format == "wide" {
+		fmt.Fprintln(tw, "NAME\tDISPLAY NAME\tVERSION\tTAGS\tEXTENDS\tVARIABLES\tDESCRIPTION")
+	} else {
+		fmt.Fprintln(tw, "NAME\tVERSION\tDESCRIPTION")
+	}
+	for _, tmpl := range templates {
+		if format == "wide" {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", tmpl.Name, tmpl.DisplayName, dash(tmpl.Version),
+				dash(strings.Join(tmpl.Tags, ",")), dash(tmpl.Extends), strconv.Itoa(len(tmpl.Variables)), tmpl.Description)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", tmpl.Name, dash(tmpl.Version), tmpl.Description)
+	}
+	return tw.Flush()
+}
+
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
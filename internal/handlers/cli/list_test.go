@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+)
+
+func TestPrintList(t *testing.T) {
+	t.Parallel()
+
+	templates := []models.TemplateMetadata{
+		{Name: "mcp", DisplayName: "MCP Ultra", Version: "1.2.0", Tags: []string{"go", "grpc"}, Description: "Servidor MCP",
+			Variables: []models.TemplateVariable{{Key: "service"}}},
+		{Name: "web", DisplayName: "web", Extends: "_base", Description: "Frontend"},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printList(&out, templates, "table", false))
+	require.Equal(t, "NAME  VERSION  DESCRIPTION\nmcp   1.2.0    Servidor MCP\nweb   -        Frontend\n", out.String())
+
+	out.Reset()
+	require.NoError(t, printList(&out, templates, "wide", false))
+	require.Regexp(t, `mcp\s+MCP Ultra\s+1\.2\.0\s+go,grpc\s+-\s+1\s+Servidor MCP`, out.String())
+	require.Regexp(t, `web\s+web\s+-\s+-\s+_base\s+0\s+Frontend`, out.String())
+
+	out.Reset()
+	require.NoError(t, printList(&out, templates, "names", false))
+	require.Equal(t, "mcp\nweb\n", out.String())
+
+	out.Reset()
+	require.NoError(t, printList(&out, templates, "names", true))
+	require.Equal(t, "mcp@1.2.0\nweb\n", out.String())
+
+	out.Reset()
+	require.NoError(t, printList(&out, templates, "yaml", false))
+	var decoded []models.TemplateMetadata
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, "MCP Ultra", decoded[0].DisplayName)
+	require.NotContains(t, out.String(), "null")
+	for _, key := range []string{"extends: \"\"", "rules:", "computed:", "runtime_funcs:", "pattern:", "secret:"} {
+		require.NotContains(t, out.String(), key)
+	}
+}
+
+func TestListFlags(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, [][]string{{"go", "grpc"}, {"http"}}, tagGroups([]string{"go, grpc", "http", " , "}))
+
+	format, err := listOutput("table", true, false)
+	require.NoError(t, err)
+	require.Equal(t, "json", format)
+	_, err = listOutput("yaml", true, true)
+	require.ErrorContains(t, err, "--json não pode ser combinado com --output yaml")
+	_, err = listOutput("xml", false, true)
+	require.ErrorContains(t, err, `--output inválido "xml"`)
+}
+
+func TestExecuteListCommandSkipsInvalidTemplate(t *testing.T) {
+	temp := setupTemplateDir(t)
+	broken := filepath.Join(temp.root, "templates", "broken")
+	require.NoError(t, os.MkdirAll(broken, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(broken, "template.yaml"), []byte("name: [broken\n"), 0o644))
+
+	out, restore := captureStdout(t)
+	require.NoError(t, ExecuteWithArgs(context.Background(), []string{"list", "--config", temp.configPath, "-o", "names", "--search", "dem"}))
+	restore()
+
+	data, err := io.ReadAll(out)
+	require.NoError(t, err)
+	_ = out.Close()
+	require.Equal(t, "demo\n", string(data))
+}
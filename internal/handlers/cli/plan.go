@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+)
+
+var planActions = []templateservice.PlanAction{
+	templateservice.PlanCreate,
+	templateservice.PlanOverwrite,
+	templateservice.PlanSkip,
+	templateservice.PlanDelete,
+}
+
+// conflictPlanActions só aparecem no resumo com --on-conflict, quando ocorrem.
+var conflictPlanActions = []templateservice.PlanAction{
+	templateservice.PlanKeep,
+	templateservice.PlanBackup,
+	templateservice.PlanConflict,
+}
+
+func printPlan(out io.Writer, plan *templateservice.Plan, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("serializar plano: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(out, "Plano para o template %s em %s (nenhum arquivo foi alterado)\n\n", plan.Template.DisplayName, plan.Output)
+	for _, file := range plan.Files {
+		fmt.Fprintf(out, "  %-10s %s (%s)\n", file.Action, file.Path, planSize(file))
+		if file.Diff != "" {
+			fmt.Fprintln(out)
+			fmt.Fprint(out, file.Diff)
+			fmt.Fprintln(out)
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprint(out, "Resumo:")
+	for _, action := range planActions {
+		fmt.Fprintf(out, " %d %s", plan.Summary[action], action)
+	}
+	for _, action := range conflictPlanActions {
+		if plan.Summary[action] > 0 {
+			fmt.Fprintf(out, " %d %s", plan.Summary[action], action)
+		}
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+func planSize(file templateservice.PlannedFile) string {
+	switch file.Action {
+	case templateservice.PlanCreate:
+		return formatBytes(file.Size)
+	case templateservice.PlanDelete:
+		return formatBytes(file.ExistingSize)
+	default:
+		return fmt.Sprintf("%s -> %s", formatBytes(file.ExistingSize), formatBytes(file.Size))
+	}
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+)
+
+func TestPrintPlanHuman(t *testing.T) {
+	t.Parallel()
+
+	plan := &templateservice.Plan{
+		Template: models.TemplateMetadata{DisplayName: "Demo"},
+		Output:   "out",
+		Files: []templateservice.PlannedFile{
+			{Path: "a.txt", Action: templateservice.PlanCreate, Size: 2048},
+			{Path: "b.txt", Action: templateservice.PlanOverwrite, Size: 10, ExistingSize: 12, Diff: "--- a/b.txt\n+++ b/b.txt\n"},
+			{Path: "c.txt", Action: templateservice.PlanDelete, ExistingSize: 5},
+		},
+		Summary: map[templateservice.PlanAction]int{
+			templateservice.PlanCreate:    1,
+			templateservice.PlanOverwrite: 1,
+			templateservice.PlanDelete:    1,
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printPlan(&out, plan, false))
+	require.Contains(t, out.String(), "create     a.txt (2.0 KiB)")
+	require.Contains(t, out.String(), "overwrite  b.txt (12 B -> 10 B)")
+	require.Contains(t, out.String(), "--- a/b.txt")
+	require.Contains(t, out.String(), "delete     c.txt (5 B)")
+	require.Contains(t, out.String(), "Resumo: 1 create 1 overwrite 0 skip 1 delete")
+}
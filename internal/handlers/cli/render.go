@@ -2,16 +2,12 @@ package cli
 
 import (
 	"bufio"
-	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 
-	"github.com/vertikon/mcp-ultra-templates/internal/models"
 	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
 )
 
@@ -19,10 +15,21 @@ func renderCommand() *cobra.Command {
 	var (
 		templateName string
 		outputDir    string
-		valuesFile   string
-		setValues    []string
+		valueArgs    valueFlags
+		profile      string
+		explain      bool
 		overwrite    bool
+		onConflict   string
+		backupDir    bool
 		interactive  bool
+		noInput      bool
+		answersFile  string
+		dryRun       bool
+		showDiff     bool
+		asJSON       bool
+		allowHooks   bool
+		now          string
+		seed         int64
 	)
 
 	cmd := &cobra.Command{
@@ -32,132 +39,143 @@ func renderCommand() *cobra.Command {
 			if templateName == "" {
 				return fmt.Errorf("--template é obrigatório")
 			}
-			if outputDir == "" {
-				return fmt.Errorf("--output é obrigatório")
-			}
 
 			app := MustApp(cmd)
 			ctx := cmd.Context()
 
-			values, err := buildValues(valuesFile, setValues)
+			if explain {
+				layers, err := valueArgs.layers()
+				if err != nil {
+					return err
+				}
+				report, err := app.TemplateService().ExplainValues(ctx, templateName, profile, layers, cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				return printValues(cmd.OutOrStdout(), report, asJSON)
+			}
+
+			if outputDir == "" {
+				return fmt.Errorf("--output é obrigatório")
+			}
+
+			values, err := valueArgs.build()
 			if err != nil {
 				return err
 			}
 
-			if interactive {
-				if err := promptMissingValues(cmd, app, ctx, templateName, values); err != nil {
+			var frozen time.Time
+			if now != "" {
+				if frozen, err = time.Parse(time.RFC3339, now); err != nil {
+					return fmt.Errorf("--now inválido, use RFC 3339 (ex.: 2025-01-10T12:00:00Z): %w", err)
+				}
+			}
+
+			var conflictMode templateservice.ConflictMode
+			if onConflict != "" {
+				if overwrite {
+					return fmt.Errorf("--overwrite e --on-conflict são mutuamente exclusivos")
+				}
+				if conflictMode, err = templateservice.ParseConflictMode(onConflict); err != nil {
+					return fmt.Errorf("--on-conflict inválido %q: use skip, overwrite, backup, prompt ou fail", onConflict)
+				}
+				if conflictMode == templateservice.ConflictPrompt && noInput {
+					return fmt.Errorf("--on-conflict=prompt não pode ser usado com --no-input")
+				}
+			}
+			if backupDir && conflictMode != templateservice.ConflictBackup && conflictMode != templateservice.ConflictPrompt {
+				return fmt.Errorf("--backup-dir requer --on-conflict=backup ou prompt")
+			}
+
+			// Assistente, segredos via stdin e prompt de conflitos compartilham a
+			// mesma entrada com buffer.
+			in := bufio.NewReader(cmd.InOrStdin())
+
+			if interactive && noInput {
+				return fmt.Errorf("--interactive e --no-input são mutuamente exclusivos")
+			}
+			if answersFile != "" && !interactive {
+				return fmt.Errorf("--save-answers requer --interactive")
+			}
+			if interactive || noInput {
+				meta, err := app.TemplateService().Template(ctx, templateName)
+				if err != nil {
 					return err
 				}
+				if noInput {
+					if err := checkRequired(meta, profile, values); err != nil {
+						return err
+					}
+				} else {
+					if err := newWizard(in, cmd.InOrStdin(), cmd.OutOrStdout(), meta, profile).run(values); err != nil {
+						return err
+					}
+					if answersFile != "" {
+						omitted, err := saveAnswers(answersFile, meta, values)
+						if err != nil {
+							return err
+						}
+						fmt.Fprintf(cmd.OutOrStdout(), "Respostas salvas em %s\n", answersFile)
+						if len(omitted) > 0 {
+							fmt.Fprintf(cmd.OutOrStdout(), "Segredos não salvos: %s; informe-os com env:, file: ou stdin.\n", strings.Join(omitted, ", "))
+						}
+					}
+				}
 			}
 
-			resp, err := app.TemplateService().Render(ctx, templateservice.RenderRequest{
+			req := templateservice.RenderRequest{
 				TemplateName: templateName,
 				OutputDir:    outputDir,
 				Values:       values,
+				Profile:      profile,
 				Overwrite:    overwrite,
-			})
+				AllowHooks:   allowHooks,
+				Now:          frozen,
+				Seed:         seed,
+				Stdin:        in,
+				OnConflict:   conflictMode,
+				BackupDir:    backupDir,
+			}
+			if conflictMode == templateservice.ConflictPrompt {
+				req.ResolveConflict = newConflictPrompt(in, cmd.OutOrStdout()).resolve
+			}
+
+			if dryRun {
+				plan, err := app.TemplateService().Plan(ctx, req, templateservice.PlanOptions{Diff: showDiff})
+				if err != nil {
+					return err
+				}
+				return printPlan(cmd.OutOrStdout(), plan, asJSON)
+			}
+
+			resp, err := app.TemplateService().Render(ctx, req)
 			if err != nil {
 				return err
 			}
 
 			fmt.Fprintf(cmd.OutOrStdout(), "Template %s renderizado em %s\n", resp.Template.DisplayName, resp.Output)
+			printConflicts(cmd.OutOrStdout(), resp.Conflicts)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&templateName, "template", "", "Nome do template")
+	cmd.Flags().StringVar(&templateName, "template", "", "Nome do template, opcionalmente com versão ou restrição semver (mcp@1.2.0, mcp@^1.1, mcp@latest)")
 	cmd.Flags().StringVar(&outputDir, "output", "", "Diretório de destino")
-	cmd.Flags().StringVar(&valuesFile, "values", "", "Arquivo YAML com variáveis")
-	cmd.Flags().StringArrayVar(&setValues, "set", nil, "Definições no formato chave=valor")
+	valueArgs.register(cmd, "Arquivo YAML com variáveis (pode ser repetido; mesclados na ordem informada)")
+	cmd.Flags().StringVar(&profile, "profile", "", "Perfil de valores declarado em profiles no template.yaml")
+	cmd.Flags().BoolVar(&explain, "explain-values", false, "Exibir a origem de cada valor resolvido sem renderizar")
 	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Permitir sobrescrever diretório de destino")
-	cmd.Flags().BoolVar(&interactive, "interactive", false, "Solicitar interativamente variáveis ausentes")
+	cmd.Flags().StringVar(&onConflict, "on-conflict", "", "Gerar em diretório existente decidindo por arquivo: skip, overwrite, backup, prompt ou fail")
+	cmd.Flags().BoolVar(&backupDir, "backup-dir", false, "Com --on-conflict=backup, guardar os originais em .mcp-backups/<data> em vez de *.orig")
+	cmd.Flags().BoolVar(&interactive, "interactive", false, "Assistente interativo para variáveis ainda sem valor, com resumo e confirmação")
+	cmd.Flags().BoolVar(&noInput, "no-input", false, "Nunca solicitar valores; falhar se variáveis obrigatórias estiverem sem valor (CI)")
+	cmd.Flags().StringVar(&answersFile, "save-answers", "", "Gravar as respostas do modo interativo em um arquivo reutilizável com --values")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Exibir o plano de renderização sem gravar arquivos")
+	cmd.Flags().BoolVar(&showDiff, "diff", false, "Incluir diff unificado no plano (com --dry-run)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Formato JSON para o plano (com --dry-run) ou os valores (com --explain-values)")
+	cmd.Flags().BoolVar(&allowHooks, "allow-hooks", false, "Permitir hooks do template que executam comandos arbitrários")
+	cmd.Flags().StringVar(&now, "now", "", "Instante fixo (RFC 3339) retornado por now em templates com runtime_funcs")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "Semente de uuid em templates com runtime_funcs (0 sorteia)")
 
 	return cmd
 }
-
-func buildValues(file string, sets []string) (map[string]string, error) {
-	values := make(map[string]string)
-
-	if file != "" {
-		data, err := os.ReadFile(filepath.Clean(file))
-		if err != nil {
-			return nil, fmt.Errorf("ler values file: %w", err)
-		}
-		if err := yaml.Unmarshal(data, &values); err != nil {
-			return nil, fmt.Errorf("parse values file: %w", err)
-		}
-	}
-
-	for _, set := range sets {
-		parts := strings.SplitN(set, "=", 2)
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("flag --set inválida, use chave=valor: %s", set)
-		}
-		values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-	}
-
-	return values, nil
-}
-
-func promptMissingValues(cmd *cobra.Command, app *App, ctx context.Context, templateName string, values map[string]string) error {
-	templates, err := app.TemplateService().List(ctx)
-	if err != nil {
-		return fmt.Errorf("listar templates: %w", err)
-	}
-
-	var meta *models.TemplateMetadata
-	for i := range templates {
-		if templates[i].Name == templateName {
-			meta = &templates[i]
-			break
-		}
-	}
-
-	if meta == nil {
-		return fmt.Errorf("template %s não encontrado", templateName)
-	}
-
-	applyDefaults(meta, values)
-
-	reader := bufio.NewReader(cmd.InOrStdin())
-	for _, variable := range meta.Variables {
-		if !variable.Required {
-			continue
-		}
-		if strings.TrimSpace(values[variable.Key]) != "" {
-			continue
-		}
-
-		for {
-			prompt := fmt.Sprintf("Informe o valor para %s", variable.Key)
-			if variable.Description != "" {
-				prompt += fmt.Sprintf(" (%s)", variable.Description)
-			}
-			prompt += ": "
-
-			fmt.Fprint(cmd.OutOrStdout(), prompt)
-			input, err := reader.ReadString('\n')
-			if err != nil {
-				return fmt.Errorf("ler valor para %s: %w", variable.Key, err)
-			}
-
-			value := strings.TrimSpace(input)
-			if value == "" {
-				fmt.Fprintln(cmd.OutOrStdout(), "Valor obrigatório, tente novamente.")
-				continue
-			}
-			values[variable.Key] = value
-			break
-		}
-	}
-
-	return nil
-}
-
-func applyDefaults(meta *models.TemplateMetadata, values map[string]string) {
-	for k, v := range meta.Defaults {
-		if _, ok := values[k]; !ok || strings.TrimSpace(values[k]) == "" {
-			values[k] = v
-		}
-	}
-}
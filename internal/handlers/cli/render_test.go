@@ -15,7 +15,7 @@ func TestBuildValues(t *testing.T) {
 	file := filepath.Join(tmp, "values.yaml")
 	require.NoError(t, os.WriteFile(file, []byte("name: demo\n"), 0o644))
 
-	values, err := buildValues(file, []string{"env=prod"})
+	values, err := (&valueFlags{files: []string{file}, sets: []string{"env=prod"}}).build()
 	require.NoError(t, err)
 	require.Equal(t, "demo", values["name"])
 	require.Equal(t, "prod", values["env"])
@@ -24,7 +24,7 @@ func TestBuildValues(t *testing.T) {
 func TestBuildValuesInvalidSet(t *testing.T) {
 	t.Parallel()
 
-	_, err := buildValues("", []string{"invalid"})
+	_, err := (&valueFlags{sets: []string{"invalid"}}).build()
 	require.Error(t, err)
 }
 
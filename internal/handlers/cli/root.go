@@ -9,10 +9,25 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/version"
 )
 
 type contextKey string
 
+// ExitError associa um código de saída específico a um erro de comando.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 var appInstance *App
 
 // Execute inicializa a CLI raiz com as subcommands configuradas.
@@ -27,8 +42,9 @@ func ExecuteWithArgs(ctx context.Context, args []string) error {
 	appInstance = nil
 
 	rootCmd := &cobra.Command{
-		Use:   "mcp-templates",
-		Short: "Gerador de projetos a partir dos templates MCP Ultra",
+		Use:     "mcp-templates",
+		Short:   "Gerador de projetos a partir dos templates MCP Ultra",
+		Version: version.Version,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			if appInstance != nil {
 				return nil
@@ -43,7 +59,11 @@ func ExecuteWithArgs(ctx context.Context, args []string) error {
 				cfg.TemplatesPath = templatesDir
 			}
 
-			appInstance = NewApp(cfg)
+			app, err := NewApp(cfg)
+			if err != nil {
+				return fmt.Errorf("inicializar repositório de templates: %w", err)
+			}
+			appInstance = app
 
 			if err := appInstance.StartObservability(cmd.Context()); err != nil {
 				return fmt.Errorf("iniciar observabilidade: %w", err)
@@ -57,7 +77,7 @@ func ExecuteWithArgs(ctx context.Context, args []string) error {
 	rootCmd.PersistentFlags().StringVar(&cfgPath, "config", "", "Arquivo de configuração YAML")
 	rootCmd.PersistentFlags().StringVar(&templatesDir, "templates-path", "", "Caminho raiz dos templates")
 
-	rootCmd.AddCommand(listCommand(), renderCommand())
+	rootCmd.AddCommand(listCommand(), inspectCommand(), renderCommand(), upgradeCommand(), lintCommand())
 
 	if args != nil {
 		rootCmd.SetArgs(args)
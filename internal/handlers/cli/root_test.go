@@ -72,6 +72,29 @@ func TestExecuteRenderCommand(t *testing.T) {
 	require.Contains(t, string(data), "demo")
 }
 
+func TestExecuteRenderCommandVersionConstraint(t *testing.T) {
+	temp := setupTemplateDir(t)
+	// Versão anterior ao lado do diretório principal, que declara 1.1.0.
+	templates := filepath.Join(temp.root, "templates")
+	require.NoError(t, os.WriteFile(filepath.Join(templates, "demo", "template.yaml"), []byte("name: demo\nversion: 1.1.0\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(templates, "demo@1.0.3"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(templates, "demo@1.0.3", "README.md"), []byte("legacy"), 0o644))
+
+	outputDir := filepath.Join(temp.root, "out-pinned")
+	args := []string{"render", "--config", temp.configPath, "--template", "demo@~1.0", "--output", outputDir}
+	require.NoError(t, ExecuteWithArgs(context.Background(), args))
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "legacy", string(data))
+	lock, err := os.ReadFile(filepath.Join(outputDir, ".mcp-template.lock"))
+	require.NoError(t, err)
+	require.Contains(t, string(lock), "template: demo\nversion: 1.0.3\n")
+
+	args = []string{"render", "--config", temp.configPath, "--template", "demo@^2", "--output", filepath.Join(temp.root, "out-missing")}
+	require.ErrorContains(t, ExecuteWithArgs(context.Background(), args), "no version of template demo satisfies ^2 (available: 1.1.0, 1.0.3)")
+}
+
 func TestExecuteRenderCommandInteractive(t *testing.T) {
 	temp := setupTemplateDirNoDefaults(t)
 
@@ -115,6 +138,42 @@ func TestExecuteRenderCommandInteractive(t *testing.T) {
 	require.Contains(t, string(data), "interactive-value")
 }
 
+func TestExecuteRenderDryRunJSON(t *testing.T) {
+	temp := setupTemplateDir(t)
+
+	outputDir := filepath.Join(temp.root, "out-plan")
+	args := []string{
+		"render",
+		"--config", temp.configPath,
+		"--template", "demo",
+		"--output", outputDir,
+		"--dry-run",
+		"--json",
+	}
+
+	out, restore := captureStdout(t)
+	require.NoError(t, ExecuteWithArgs(context.Background(), args))
+	restore()
+
+	data, err := io.ReadAll(out)
+	require.NoError(t, err)
+	_ = out.Close()
+
+	var plan struct {
+		Files []struct {
+			Path   string `json:"path"`
+			Action string `json:"action"`
+		} `json:"files"`
+	}
+	require.NoError(t, json.Unmarshal(data, &plan))
+	require.Len(t, plan.Files, 1)
+	require.Equal(t, "README.md", plan.Files[0].Path)
+	require.Equal(t, "create", plan.Files[0].Action)
+
+	_, err = os.Stat(outputDir)
+	require.True(t, os.IsNotExist(err))
+}
+
 func TestExecuteUsesOSArgs(t *testing.T) {
 	temp := setupTemplateDir(t)
 
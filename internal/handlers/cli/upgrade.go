@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+)
+
+// upgradeExitConflicts é o código de saída quando a atualização foi aplicada,
+// mas deixou arquivos em conflito para resolução manual.
+const upgradeExitConflicts = 2
+
+var upgradeActions = []templateservice.UpgradeAction{
+	templateservice.UpgradeAdded,
+	templateservice.UpgradeUpdated,
+	templateservice.UpgradeMerged,
+	templateservice.UpgradeRemoved,
+	templateservice.UpgradeKept,
+	templateservice.UpgradeConflict,
+	templateservice.UpgradeUnchanged,
+}
+
+func upgradeCommand() *cobra.Command {
+	var (
+		outputDir  string
+		toVersion  string
+		valueArgs  valueFlags
+		asJSON     bool
+		allowHooks bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Atualiza um projeto gerado para uma nova versão do template",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if outputDir == "" {
+				return fmt.Errorf("--output é obrigatório")
+			}
+
+			app := MustApp(cmd)
+
+			values, err := valueArgs.build()
+			if err != nil {
+				return err
+			}
+
+			result, err := app.TemplateService().Upgrade(cmd.Context(), templateservice.UpgradeRequest{
+				OutputDir:  outputDir,
+				ToVersion:  toVersion,
+				Values:     values,
+				AllowHooks: allowHooks,
+			})
+			if err != nil {
+				return err
+			}
+			if err := printUpgrade(cmd.OutOrStdout(), result, asJSON); err != nil {
+				return err
+			}
+			return upgradeExit(result)
+		},
+	}
+
+	cmd.Flags().StringVar(&outputDir, "output", "", "Diretório do projeto gerado")
+	cmd.Flags().StringVar(&toVersion, "to", "", "Versão alvo do template, exata ou restrição semver como ^1.2 (padrão: a mais recente)")
+	valueArgs.register(cmd, "Arquivo YAML com variáveis que sobrescrevem o lockfile")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Formato JSON")
+	cmd.Flags().BoolVar(&allowHooks, "allow-hooks", false, "Permitir hooks do template que executam comandos arbitrários")
+
+	return cmd
+}
+
+func printUpgrade(out io.Writer, result *templateservice.UpgradeResult, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("serializar resultado: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(out, "Template %s atualizado de %s para %s em %s\n\n", result.Template, result.FromVersion, result.ToVersion, result.Output)
+	for _, file := range result.Files {
+		if file.Action == templateservice.UpgradeUnchanged {
+			continue
+		}
+		if file.Reason != "" {
+			fmt.Fprintf(out, "  %-10s %s (%s)\n", file.Action, file.Path, file.Reason)
+			continue
+		}
+		fmt.Fprintf(out, "  %-10s %s\n", file.Action, file.Path)
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprint(out, "Resumo:")
+	for _, action := range upgradeActions {
+		fmt.Fprintf(out, " %d %s", result.Summary[action], action)
+	}
+	fmt.Fprintln(out)
+
+	if conflicts := result.Conflicts(); conflicts > 0 {
+		fmt.Fprintf(out, "\n%d arquivo(s) com conflito: resolva os marcadores <<<<<<< e os arquivos %s antes de versionar.\n", conflicts, templateservice.RejectSuffix)
+	}
+	return nil
+}
+
+// upgradeExit retorna um *ExitError quando restam conflitos, para que scripts e
+// CI não tratem a atualização como concluída.
+func upgradeExit(result *templateservice.UpgradeResult) error {
+	if conflicts := result.Conflicts(); conflicts > 0 {
+		return &ExitError{Code: upgradeExitConflicts, Err: fmt.Errorf("upgrade deixou %d arquivo(s) com conflito", conflicts)}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+)
+
+func TestPrintUpgradeHuman(t *testing.T) {
+	t.Parallel()
+
+	result := &templateservice.UpgradeResult{
+		Template:    "demo",
+		FromVersion: "1.0.0",
+		ToVersion:   "1.1.0",
+		Output:      "out",
+		Files: []templateservice.UpgradedFile{
+			{Path: "a.txt", Action: templateservice.UpgradeMerged},
+			{Path: "b.txt", Action: templateservice.UpgradeConflict, Reason: "marcadores de conflito no arquivo"},
+			{Path: "c.txt", Action: templateservice.UpgradeUnchanged},
+		},
+		Summary: map[templateservice.UpgradeAction]int{
+			templateservice.UpgradeMerged:    1,
+			templateservice.UpgradeConflict:  1,
+			templateservice.UpgradeUnchanged: 1,
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printUpgrade(&out, result, false))
+	require.Contains(t, out.String(), "atualizado de 1.0.0 para 1.1.0")
+	require.Contains(t, out.String(), "merged     a.txt\n")
+	require.Contains(t, out.String(), "conflict   b.txt (marcadores de conflito no arquivo)")
+	require.NotContains(t, out.String(), "c.txt")
+	require.Contains(t, out.String(), "1 merged 0 removed 0 kept 1 conflict 1 unchanged")
+	require.Contains(t, out.String(), "1 arquivo(s) com conflito")
+
+	var exitErr *ExitError
+	require.ErrorAs(t, upgradeExit(result), &exitErr)
+	require.Equal(t, upgradeExitConflicts, exitErr.Code)
+	result.Summary[templateservice.UpgradeConflict] = 0
+	require.NoError(t, upgradeExit(result))
+}
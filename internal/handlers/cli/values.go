@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// valueFlags reúne as flags que definem valores do template. São aplicadas, em
+// profundidade e nesta ordem, sobre os defaults e o perfil: arquivos --values na
+// ordem da linha de comando, --set-json, --set e --set-file.
+type valueFlags struct {
+	files    []string
+	setJSON  []string
+	sets     []string
+	setFiles []string
+}
+
+func (f *valueFlags) register(cmd *cobra.Command, fileUsage string) {
+	cmd.Flags().StringArrayVar(&f.files, "values", nil, fileUsage)
+	cmd.Flags().StringArrayVar(&f.setJSON, "set-json", nil, "Definições no formato chave=JSON (listas e mapas)")
+	cmd.Flags().StringArrayVar(&f.sets, "set", nil, "Definições no formato chave=valor; a chave aceita caminhos como a.b.c")
+	cmd.Flags().StringArrayVar(&f.setFiles, "set-file", nil, "Definições no formato chave=arquivo, usando o conteúdo do arquivo")
+}
+
+func (f *valueFlags) build() (map[string]any, error) {
+	layers, err := f.layers()
+	if err != nil {
+		return nil, err
+	}
+	values, _ := valuespkg.Resolve(layers)
+	return values, nil
+}
+
+// layers retorna uma camada por arquivo ou flag, na ordem de precedência.
+func (f *valueFlags) layers() ([]valuespkg.Layer, error) {
+	var layers []valuespkg.Layer
+
+	for _, file := range f.files {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, fmt.Errorf("ler values file: %w", err)
+		}
+		var fromFile map[string]any
+		if err := yaml.Unmarshal(data, &fromFile); err != nil {
+			return nil, fmt.Errorf("parse values file %s: %w", file, err)
+		}
+		if fromFile == nil {
+			continue
+		}
+		layers = append(layers, valuespkg.Layer{Source: file, Values: valuespkg.Normalize(fromFile).(map[string]any)})
+	}
+
+	for _, set := range f.setJSON {
+		key, raw, err := splitAssignment("--set-json", set)
+		if err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("flag --set-json inválida para %s: %w", key, err)
+		}
+		layer, err := flagLayer("--set-json", key, valuespkg.Normalize(value))
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+
+	for _, set := range f.sets {
+		key, value, err := splitAssignment("--set", set)
+		if err != nil {
+			return nil, err
+		}
+		layer, err := flagLayer("--set", key, strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+
+	for _, set := range f.setFiles {
+		key, path, err := splitAssignment("--set-file", set)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(filepath.Clean(strings.TrimSpace(path)))
+		if err != nil {
+			return nil, fmt.Errorf("ler arquivo de --set-file para %s: %w", key, err)
+		}
+		layer, err := flagLayer("--set-file", key, string(data))
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+
+	return layers, nil
+}
+
+func splitAssignment(flag, assignment string) (string, string, error) {
+	parts := strings.SplitN(assignment, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return "", "", fmt.Errorf("flag %s inválida, use chave=valor: %s", flag, assignment)
+	}
+	return strings.TrimSpace(parts[0]), parts[1], nil
+}
+
+// flagLayer cria a camada de uma única definição. Um mapa informado é mesclado
+// ao mapa já existente no mesmo caminho.
+func flagLayer(flag, key string, value any) (valuespkg.Layer, error) {
+	values := make(map[string]any)
+	if err := valuespkg.Set(values, key, value); err != nil {
+		return valuespkg.Layer{}, fmt.Errorf("chave inválida: %s", key)
+	}
+	return valuespkg.Layer{Source: flag + " " + key, Values: values}, nil
+}
+
+func printValues(out io.Writer, report *templateservice.ValuesReport, asJSON bool) error {
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("serializar valores: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	header := fmt.Sprintf("Valores do template %s", report.Template)
+	if report.Profile != "" {
+		header += fmt.Sprintf(" (perfil %s)", report.Profile)
+	}
+	fmt.Fprintln(out, header+"\n")
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, value := range report.Values {
+		fmt.Fprintf(w, "  %s\t= %s\t%s\n", value.Path, valuespkg.String(value.Value), value.Source)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+func TestBuildValuesNested(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "values.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`database:
+  host: localhost
+  port: 5432
+services:
+  - name: users
+`), 0o644))
+	cert := filepath.Join(tmp, "cert.pem")
+	require.NoError(t, os.WriteFile(cert, []byte("-----BEGIN-----\n"), 0o644))
+
+	values, err := (&valueFlags{
+		files:    []string{file},
+		setJSON:  []string{`subjects=["orders.created","orders.paid"]`, `database={"user":"app"}`},
+		sets:     []string{"database.host=db.internal", "subjects=ignored.by.set-file"},
+		setFiles: []string{"tls.cert=" + cert, "subjects=" + cert},
+	}).build()
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]any{
+		"host": "db.internal",
+		"port": 5432,
+		"user": "app",
+	}, values["database"])
+	require.Equal(t, []any{map[string]any{"name": "users"}}, values["services"])
+	require.Equal(t, map[string]any{"cert": "-----BEGIN-----\n"}, values["tls"])
+	require.Equal(t, "-----BEGIN-----\n", values["subjects"])
+}
+
+func TestBuildValuesSetJSONNumbers(t *testing.T) {
+	t.Parallel()
+
+	values, err := (&valueFlags{setJSON: []string{"replicas=3"}}).build()
+	require.NoError(t, err)
+	require.Equal(t, json.Number("3"), values["replicas"])
+}
+
+func TestBuildValuesInvalidInputs(t *testing.T) {
+	t.Parallel()
+
+	_, err := (&valueFlags{setJSON: []string{"list=[1,"}}).build()
+	require.ErrorContains(t, err, "--set-json")
+
+	_, err = (&valueFlags{sets: []string{"a..b=x"}}).build()
+	require.ErrorContains(t, err, "chave inválida")
+
+	_, err = (&valueFlags{setFiles: []string{"key=" + filepath.Join(t.TempDir(), "missing")}}).build()
+	require.ErrorContains(t, err, "--set-file")
+}
+
+func TestValueLayersFollowCommandLineOrder(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	common := filepath.Join(tmp, "common.yaml")
+	prod := filepath.Join(tmp, "prod.yaml")
+	empty := filepath.Join(tmp, "empty.yaml")
+	require.NoError(t, os.WriteFile(common, []byte("replicas: 1\ndatabase:\n  host: localhost\n  port: 5432\n"), 0o644))
+	require.NoError(t, os.WriteFile(prod, []byte("replicas: 3\ndatabase:\n  host: db.prod\n"), 0o644))
+	require.NoError(t, os.WriteFile(empty, nil, 0o644))
+
+	flags := &valueFlags{files: []string{common, empty, prod}, sets: []string{"database.port=6432"}}
+	layers, err := flags.layers()
+	require.NoError(t, err)
+
+	values, origins := valuespkg.Resolve(layers)
+	require.Equal(t, 3, values["replicas"])
+	require.Equal(t, map[string]any{"host": "db.prod", "port": "6432"}, values["database"])
+	require.Equal(t, map[string]string{
+		"replicas":      prod,
+		"database.host": prod,
+		"database.port": "--set database.port",
+	}, origins)
+}
+
+func TestPrintValues(t *testing.T) {
+	t.Parallel()
+
+	report := &templateservice.ValuesReport{
+		Template: "mcp",
+		Profile:  "prod",
+		Values: []templateservice.ValueOrigin{
+			{Path: "database.host", Value: "db.prod", Source: "profile prod"},
+			{Path: "replicas", Value: 3, Source: "values.yaml"},
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printValues(&out, report, false))
+	require.Contains(t, out.String(), "Valores do template mcp (perfil prod)")
+	require.Contains(t, out.String(), "  database.host  = db.prod  profile prod\n")
+	require.Contains(t, out.String(), "  replicas       = 3        values.yaml\n")
+
+	out.Reset()
+	require.NoError(t, printValues(&out, report, true))
+	require.Contains(t, out.String(), `"source": "profile prod"`)
+}
@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	templateservice "github.com/vertikon/mcp-ultra-templates/internal/services/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// errCancelled indica que o usuário recusou o resumo do modo interativo.
+var errCancelled = errors.New("renderização cancelada")
+
+// wizard conduz o modo interativo: pergunta as variáveis obrigatórias ainda sem
+// valor, depois, se o usuário quiser, as opcionais, e confirma um resumo antes
+// da renderização. Variáveis computadas não são perguntadas.
+type wizard struct {
+	in       *bufio.Reader
+	out      io.Writer
+	meta     *models.TemplateMetadata
+	defaults map[string]any
+	// readSecret lê variáveis secretas sem eco; nil quando a entrada não é um
+	// terminal.
+	readSecret func() (string, error)
+}
+
+// newWizard cria o assistente lendo as respostas de in. terminal é a entrada
+// original, antes de qualquer buffer, usada para ler segredos sem eco quando é
+// um terminal; pode ser nil.
+func newWizard(in, terminal io.Reader, out io.Writer, meta *models.TemplateMetadata, profile string) *wizard {
+	w := &wizard{
+		in:       bufio.NewReader(in),
+		out:      out,
+		meta:     meta,
+		defaults: valuespkg.Merge(valuespkg.Merge(nil, meta.Defaults), meta.Profiles[profile]),
+	}
+	if file, ok := terminal.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		w.readSecret = func() (string, error) {
+			data, err := term.ReadPassword(int(file.Fd()))
+			fmt.Fprintln(w.out)
+			return strings.TrimSpace(string(data)), err
+		}
+	}
+	return w
+}
+
+// run completa values com as respostas.
+func (w *wizard) run(values map[string]any) error {
+	var stdinErr error
+	valuespkg.Walk(values, func(path string, value any) {
+		if value == templateservice.SecretFromStdin {
+			stdinErr = fmt.Errorf("%s usa stdin, indisponível com --interactive; use env: ou file:", path)
+		}
+	})
+	if stdinErr != nil {
+		return stdinErr
+	}
+
+	var required, optional []models.TemplateVariable
+	for _, variable := range w.meta.Variables {
+		if _, computed := w.meta.Computed[variable.Key]; computed {
+			continue
+		}
+		if _, ok := valuespkg.Lookup(values, variable.Key); ok {
+			continue
+		}
+		if variable.Required {
+			required = append(required, variable)
+		} else {
+			optional = append(optional, variable)
+		}
+	}
+
+	for _, variable := range required {
+		if err := w.ask(variable, values); err != nil {
+			return err
+		}
+	}
+	if len(optional) > 0 {
+		advanced, err := w.confirm(fmt.Sprintf("Configurar %d variável(is) opcional(is) (avançado)?", len(optional)), false)
+		if err != nil {
+			return err
+		}
+		for _, variable := range optional {
+			if !advanced {
+				break
+			}
+			if err := w.ask(variable, values); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := w.summary(values); err != nil {
+		return err
+	}
+	ok, err := w.confirm("Confirmar renderização?", true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errCancelled
+	}
+	return nil
+}
+
+// ask pergunta uma variável até receber um valor válido. Enter aceita o
+// default; sem default, deixa opcionais em branco.
+func (w *wizard) ask(variable models.TemplateVariable, values map[string]any) error {
+	def, hasDefault := valuespkg.Lookup(w.defaults, variable.Key)
+	hasDefault = hasDefault && valuespkg.String(def) != ""
+
+	prompt := variable.Key
+	if variable.Description != "" {
+		prompt += fmt.Sprintf(" (%s)", variable.Description)
+	}
+	if hint := typeHint(variable); hint != "" {
+		prompt += " <" + hint + ">"
+	}
+	if hasDefault {
+		prompt += fmt.Sprintf(" [%s]", w.display(variable, def))
+	}
+
+	if variable.Type == models.VariableTypeEnum {
+		for i, choice := range variable.Choices {
+			fmt.Fprintf(w.out, "  %d) %s\n", i+1, choice)
+		}
+	}
+
+	for {
+		fmt.Fprint(w.out, prompt+": ")
+		readLine := w.readLine
+		if variable.Secret && w.readSecret != nil {
+			readLine = w.readSecret
+		}
+		line, err := readLine()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("ler valor para %s: %w", variable.Key, err)
+		}
+
+		if line == "" {
+			switch {
+			case hasDefault:
+				return valuespkg.Set(values, variable.Key, valuespkg.Clone(def))
+			case !variable.Required:
+				return nil
+			case err != nil:
+				return fmt.Errorf("ler valor para %s: entrada encerrada", variable.Key)
+			}
+			fmt.Fprintln(w.out, "Valor obrigatório, tente novamente.")
+			continue
+		}
+
+		value, invalid := parseAnswer(variable, line)
+		if invalid != nil {
+			fmt.Fprintf(w.out, "%v, tente novamente.\n", invalid)
+			continue
+		}
+		return valuespkg.Set(values, variable.Key, value)
+	}
+}
+
+// parseAnswer converte e valida a resposta conforme o tipo da variável. Em enum,
+// aceita o número da opção; list e map são informados em JSON.
+func parseAnswer(variable models.TemplateVariable, answer string) (any, error) {
+	switch variable.Type {
+	case models.VariableTypeEnum:
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(variable.Choices) {
+			answer = variable.Choices[n-1]
+		}
+	case models.VariableTypeList, models.VariableTypeMap:
+		decoder := json.NewDecoder(strings.NewReader(answer))
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil, fmt.Errorf("JSON inválido para %s: %v", variable.Key, err)
+		}
+		value = valuespkg.Normalize(value)
+		_, isList := value.([]any)
+		_, isMap := value.(map[string]any)
+		if (variable.Type == models.VariableTypeList && !isList) || (variable.Type == models.VariableTypeMap && !isMap) {
+			return nil, fmt.Errorf("esperado %s em JSON para %s", variable.Type, variable.Key)
+		}
+		return value, nil
+	}
+
+	if variable.Secret && templateservice.IsSecretReference(answer) {
+		if answer == templateservice.SecretFromStdin {
+			return nil, fmt.Errorf("stdin indisponível no modo interativo; use env: ou file:")
+		}
+		return answer, nil
+	}
+	if err := templateservice.ValidateValue(variable, answer); err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+func typeHint(variable models.TemplateVariable) string {
+	switch variable.Type {
+	case models.VariableTypeBool:
+		return "true/false"
+	case models.VariableTypeInt:
+		return "inteiro"
+	case models.VariableTypeEnum:
+		return fmt.Sprintf("1-%d ou valor", len(variable.Choices))
+	case models.VariableTypeList:
+		return `JSON, ex.: ["a","b"]`
+	case models.VariableTypeMap:
+		return `JSON, ex.: {"chave":"valor"}`
+	}
+	if variable.Secret {
+		return "segredo; aceita env:NOME ou file:caminho"
+	}
+	return ""
+}
+
+// summary lista o valor final de cada variável, marcando os defaults.
+func (w *wizard) summary(values map[string]any) error {
+	fmt.Fprintf(w.out, "\nResumo do template %s:\n", w.meta.Name)
+	tw := tabwriter.NewWriter(w.out, 0, 0, 2, ' ', 0)
+	for _, variable := range w.meta.Variables {
+		if _, computed := w.meta.Computed[variable.Key]; computed {
+			continue
+		}
+		value, ok := valuespkg.Lookup(values, variable.Key)
+		origin := ""
+		if !ok {
+			value, ok = valuespkg.Lookup(w.defaults, variable.Key)
+			origin = "(padrão)"
+		}
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t= %s\t%s\n", variable.Key, w.display(variable, value), origin)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w.out)
+	return nil
+}
+
+// confirm faz uma pergunta sim/não; Enter ou fim da entrada assumem def.
+func (w *wizard) confirm(question string, def bool) (bool, error) {
+	options := "[s/N]"
+	if def {
+		options = "[S/n]"
+	}
+	for {
+		fmt.Fprintf(w.out, "%s %s: ", question, options)
+		line, err := w.readLine()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, fmt.Errorf("ler resposta: %w", err)
+		}
+		switch strings.ToLower(line) {
+		case "":
+			return def, nil
+		case "s", "sim", "y", "yes":
+			return true, nil
+		case "n", "não", "nao", "no":
+			return false, nil
+		}
+		fmt.Fprintln(w.out, "Responda s ou n.")
+	}
+}
+
+// readLine lê uma linha sem espaços nas pontas; io.EOF indica fim da entrada.
+func (w *wizard) readLine() (string, error) {
+	line, err := w.in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if errors.Is(err, io.EOF) && line != "" {
+		err = nil
+	}
+	return line, err
+}
+
+func (w *wizard) display(variable models.TemplateVariable, value any) string {
+	text := valuespkg.String(value)
+	if variable.Secret && text != "" && !templateservice.IsSecretReference(text) {
+		return templateservice.MaskedValue
+	}
+	return text
+}
+
+// saveAnswers grava values como arquivo reutilizável com --values. Segredos
+// literais são omitidos; referências env: e file: são mantidas. Retorna as
+// chaves omitidas.
+func saveAnswers(path string, meta *models.TemplateMetadata, values map[string]any) ([]string, error) {
+	answers := valuespkg.Merge(nil, values)
+	var omitted []string
+	for _, variable := range meta.Variables {
+		if !variable.Secret {
+			continue
+		}
+		value, ok := valuespkg.Lookup(answers, variable.Key)
+		if !ok || templateservice.IsSecretReference(valuespkg.String(value)) {
+			continue
+		}
+		omitted = append(omitted, variable.Key)
+		valuespkg.Delete(answers, variable.Key)
+	}
+	sort.Strings(omitted)
+
+	data, err := yaml.Marshal(answers)
+	if err != nil {
+		return nil, fmt.Errorf("serializar respostas: %w", err)
+	}
+	header := fmt.Sprintf("# Respostas do modo interativo para o template %s. Reutilize com --values.\n", meta.Name)
+	if err := os.WriteFile(filepath.Clean(path), append([]byte(header), data...), 0o644); err != nil {
+		return nil, fmt.Errorf("gravar respostas: %w", err)
+	}
+	return omitted, nil
+}
+
+// checkRequired falha, sem perguntar nada, quando variáveis obrigatórias não
+// têm valor nem default. Usado por --no-input.
+func checkRequired(meta *models.TemplateMetadata, profile string, values map[string]any) error {
+	effective := valuespkg.Merge(valuespkg.Merge(valuespkg.Merge(nil, meta.Defaults), meta.Profiles[profile]), values)
+	var missing []string
+	for _, variable := range meta.Variables {
+		if _, computed := meta.Computed[variable.Key]; computed || !variable.Required {
+			continue
+		}
+		value, _ := valuespkg.Lookup(effective, variable.Key)
+		if strings.TrimSpace(valuespkg.String(value)) == "" {
+			missing = append(missing, variable.Key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("variáveis obrigatórias sem valor com --no-input: %s (use --set, --values ou --profile)", strings.Join(missing, ", "))
+	}
+	return nil
+}
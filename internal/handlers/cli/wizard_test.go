@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+)
+
+func wizardMetadata() *models.TemplateMetadata {
+	return &models.TemplateMetadata{
+		Name: "demo",
+		Variables: []models.TemplateVariable{
+			{Key: "service_name", Required: true, Pattern: "[a-z][a-z0-9-]*"},
+			{Key: "environment", Required: true, Type: models.VariableTypeEnum, Choices: []string{"dev", "staging", "prod"}},
+			{Key: "replicas", Required: true, Type: models.VariableTypeInt},
+			{Key: "module_name", Required: true},
+			{Key: "db.password", Secret: true},
+			{Key: "subjects", Type: models.VariableTypeList},
+			{Key: "enable_nats", Type: models.VariableTypeBool},
+			{Key: "binary_name"},
+		},
+		Defaults: map[string]any{"replicas": 1, "enable_nats": "true"},
+		Profiles: map[string]map[string]any{"prod": {"replicas": 3}},
+		Computed: map[string]string{"binary_name": "{{ .service_name }}"},
+	}
+}
+
+func TestWizardRun(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Join([]string{
+		"Order Service", // inválido pelo pattern
+		"orders",
+		"9", // fora das opções
+		"3",
+		"",  // aceita o default do perfil
+		"s", // variáveis opcionais
+		"Sup3r-Secret",
+		`{"a": 1}`, // não é lista
+		`["orders.created"]`,
+		"",
+		"",
+	}, "\n") + "\n"
+	var out bytes.Buffer
+	values := map[string]any{"module_name": "github.com/acme/orders"}
+
+	err := newWizard(strings.NewReader(input), nil, &out, wizardMetadata(), "prod").run(values)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"service_name": "orders",
+		"environment":  "prod",
+		"replicas":     3,
+		"module_name":  "github.com/acme/orders",
+		"db":           map[string]any{"password": "Sup3r-Secret"},
+		"subjects":     []any{"orders.created"},
+		"enable_nats":  "true",
+	}, values)
+
+	output := out.String()
+	require.Contains(t, output, "não corresponde ao pattern")
+	require.Contains(t, output, "  3) prod")
+	require.Contains(t, output, "fora das opções")
+	require.Contains(t, output, "replicas <inteiro> [3]: ")
+	require.Contains(t, output, "esperado list em JSON")
+	require.Contains(t, output, "enable_nats <true/false> [true]: ")
+	require.Regexp(t, `db\.password += \*{8} `, output)
+	require.NotContains(t, output, "Sup3r-Secret")
+	require.NotContains(t, output, "binary_name")
+	require.Contains(t, output, "Confirmar renderização? [S/n]: ")
+}
+
+func TestWizardSkipsOptionalAndCancels(t *testing.T) {
+	t.Parallel()
+
+	meta := wizardMetadata()
+	values := map[string]any{"service_name": "orders", "environment": "dev", "replicas": 1, "module_name": "m"}
+
+	var out bytes.Buffer
+	err := newWizard(strings.NewReader("\nn\n"), nil, &out, meta, "").run(values)
+	require.ErrorIs(t, err, errCancelled)
+	require.Regexp(t, `enable_nats += true +\(padrão\)`, out.String())
+	require.NotContains(t, out.String(), "subjects <")
+
+	err = newWizard(strings.NewReader(""), nil, &out, meta, "").run(map[string]any{})
+	require.ErrorContains(t, err, "service_name: entrada encerrada")
+
+	err = newWizard(strings.NewReader(""), nil, &out, meta, "").run(map[string]any{"db": map[string]any{"password": "stdin"}})
+	require.ErrorContains(t, err, "db.password usa stdin")
+}
+
+func TestSaveAnswers(t *testing.T) {
+	t.Parallel()
+
+	meta := wizardMetadata()
+	meta.Variables = append(meta.Variables, models.TemplateVariable{Key: "jwt_secret", Secret: true})
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+
+	omitted, err := saveAnswers(path, meta, map[string]any{
+		"service_name": "orders",
+		"db":           map[string]any{"password": "Sup3r-Secret"},
+		"jwt_secret":   "env:JWT_SECRET",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"db.password"}, omitted)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "Sup3r-Secret")
+	var saved map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &saved))
+	require.Equal(t, map[string]any{"service_name": "orders", "db": map[string]any{}, "jwt_secret": "env:JWT_SECRET"}, saved)
+}
+
+func TestCheckRequired(t *testing.T) {
+	t.Parallel()
+
+	meta := wizardMetadata()
+	err := checkRequired(meta, "", map[string]any{"service_name": "orders"})
+	require.EqualError(t, err, "variáveis obrigatórias sem valor com --no-input: environment, module_name (use --set, --values ou --profile)")
+
+	require.NoError(t, checkRequired(meta, "", map[string]any{"service_name": "orders", "environment": "dev", "module_name": "m"}))
+}
+
+func TestExecuteRenderCommandNoInput(t *testing.T) {
+	temp := setupTemplateDirNoDefaults(t)
+
+	outputDir := filepath.Join(temp.root, "out-ci")
+	base := []string{"render", "--config", temp.configPath, "--template", "demo", "--output", outputDir}
+
+	err := ExecuteWithArgs(context.Background(), append(base, "--no-input"))
+	require.ErrorContains(t, err, "variáveis obrigatórias sem valor com --no-input: project")
+	_, statErr := os.Stat(outputDir)
+	require.True(t, os.IsNotExist(statErr))
+
+	err = ExecuteWithArgs(context.Background(), append(base, "--no-input", "--interactive"))
+	require.ErrorContains(t, err, "mutuamente exclusivos")
+
+	err = ExecuteWithArgs(context.Background(), append(base, "--save-answers", "answers.yaml"))
+	require.ErrorContains(t, err, "--save-answers requer --interactive")
+
+	require.NoError(t, ExecuteWithArgs(context.Background(), append(base, "--no-input", "--set", "project=ci")))
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Lockfile registra a proveniência de um projeto gerado: template, versão,
+// valores resolvidos e o hash de cada arquivo renderizado.
+type Lockfile struct {
+	Template    string            `yaml:"template" json:"template"`
+	Version     string            `yaml:"version" json:"version"`
+	SourceHash  string            `yaml:"source_hash" json:"source_hash"`
+	CLIVersion  string            `yaml:"cli_version" json:"cli_version"`
+	GeneratedAt time.Time         `yaml:"generated_at" json:"generated_at"`
+	Profile     string            `yaml:"profile,omitempty" json:"profile,omitempty"`
+	Values      map[string]any    `yaml:"values" json:"values"`
+	Files       map[string]string `yaml:"files" json:"files"`
+	// Runtime é registrado quando o template usa now e uuid.
+	Runtime *RuntimeState `yaml:"runtime,omitempty" json:"runtime,omitempty"`
+}
+
+// RuntimeState registra o instante e a semente usados por now e uuid, permitindo
+// reproduzir a renderização.
+type RuntimeState struct {
+	Now  time.Time `yaml:"now" json:"now"`
+	Seed int64     `yaml:"seed" json:"seed"`
+}
@@ -1,20 +1,191 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
 // TemplateMetadata descreve um template disponível para geração.
 type TemplateMetadata struct {
-	Name        string              `yaml:"name" json:"name"`
-	DisplayName string              `yaml:"display_name" json:"display_name"`
-	Description string              `yaml:"description" json:"description"`
-	Version     string              `yaml:"version" json:"version"`
-	Variables   []TemplateVariable  `yaml:"variables" json:"variables"`
-	Tags        []string            `yaml:"tags" json:"tags"`
-	Defaults    map[string]string   `yaml:"defaults" json:"defaults"`
+	Name        string             `yaml:"name" json:"name"`
+	DisplayName string             `yaml:"display_name" json:"display_name"`
+	Description string             `yaml:"description" json:"description"`
+	Version     string             `yaml:"version" json:"version"`
+	Variables   []TemplateVariable `yaml:"variables" json:"variables"`
+	Tags        []string           `yaml:"tags" json:"tags"`
+	Defaults    map[string]any     `yaml:"defaults" json:"defaults"`
+	Rules       []FileRule         `yaml:"rules,omitempty" json:"rules,omitempty"`
+	// Profiles são sobreposições de valores por ambiente, aplicadas sobre Defaults.
+	Profiles map[string]map[string]any `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+	// Computed define variáveis derivadas de expressões de template sobre os
+	// demais valores, como "{{ kebab .service_name }}".
+	Computed map[string]string `yaml:"computed,omitempty" json:"computed,omitempty"`
+	// Extends indica o template base cujos arquivos e metadados são herdados.
+	Extends string `yaml:"extends,omitempty" json:"extends,omitempty"`
+	// Partials lista diretórios, relativos ao template, com templates nomeados compartilhados.
+	Partials []string `yaml:"partials,omitempty" json:"partials,omitempty"`
+	Hooks    Hooks    `yaml:"hooks" json:"hooks"`
+	// Delimiters troca os delimitadores de ação, no template inteiro ou por padrão de caminho.
+	Delimiters []Delimiters `yaml:"delimiters,omitempty" json:"delimiters,omitempty"`
+	// CopyOnly lista arquivos copiados sem interpretação; RenderOnly restringe a
+	// interpretação aos arquivos que casam.
+	CopyOnly   []string `yaml:"copy_only,omitempty" json:"copy_only,omitempty"`
+	RenderOnly []string `yaml:"render_only,omitempty" json:"render_only,omitempty"`
+	// RuntimeFuncs habilita now e uuid, cujos resultados ficam registrados no
+	// lockfile para reprodução.
+	RuntimeFuncs bool `yaml:"runtime_funcs,omitempty" json:"runtime_funcs,omitempty"`
+}
+
+// Delimiters define os delimitadores de ação dos arquivos que casam com Paths.
+// Sem Paths, vale para o template inteiro; a última entrada aplicável vence.
+type Delimiters struct {
+	Left  string   `yaml:"left" json:"left"`
+	Right string   `yaml:"right" json:"right"`
+	Paths []string `yaml:"paths,omitempty" json:"paths,omitempty"`
 }
 
-// TemplateVariable define os campos parametrizáveis.
+// FileRule inclui ou exclui arquivos do template conforme uma condição sobre os valores.
+// Exatamente um entre Include e Exclude deve ser informado.
+type FileRule struct {
+	Include string `yaml:"include,omitempty" json:"include,omitempty"`
+	Exclude string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+	When    string `yaml:"when,omitempty" json:"when,omitempty"`
+}
+
+// VariableType identifica o tipo de valor aceito por uma variável.
+type VariableType string
+
+// Tipos de variáveis suportados em template.yaml.
+const (
+	VariableTypeString   VariableType = "string"
+	VariableTypeInt      VariableType = "int"
+	VariableTypeBool     VariableType = "bool"
+	VariableTypeEnum     VariableType = "enum"
+	VariableTypeGoModule VariableType = "go_module"
+	VariableTypeList     VariableType = "list"
+	VariableTypeMap      VariableType = "map"
+)
+
+// TemplateVariable define os campos parametrizáveis. Key aceita caminhos
+// aninhados separados por ponto, como "database.host".
 type TemplateVariable struct {
-	Key         string `yaml:"key" json:"key"`
-	Description string `yaml:"description" json:"description"`
-	Required    bool   `yaml:"required" json:"required"`
+	Key         string       `yaml:"key" json:"key"`
+	Description string       `yaml:"description" json:"description"`
+	Required    bool         `yaml:"required" json:"required"`
+	Type        VariableType `yaml:"type,omitempty" json:"type,omitempty"`
+	Choices     []string     `yaml:"choices,omitempty" json:"choices,omitempty"`
+	Pattern     string       `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	MinLength   int          `yaml:"min_length,omitempty" json:"min_length,omitempty"`
+	MaxLength   int          `yaml:"max_length,omitempty" json:"max_length,omitempty"`
+	// Secret oculta o valor em logs, erros, relatórios e no lockfile e permite
+	// lê-lo de env:NOME, file:caminho ou stdin.
+	Secret bool `yaml:"secret,omitempty" json:"secret,omitempty"`
 }
 
+// HookAction identifica a ação executada por um passo de hook.
+type HookAction string
+
+// Ações de hook suportadas em template.yaml.
+const (
+	HookGofmt     HookAction = "gofmt"
+	HookGoModTidy HookAction = "go_mod_tidy"
+	HookGitInit   HookAction = "git_init"
+	HookChmod     HookAction = "chmod"
+	HookCommand   HookAction = "command"
+)
+
+// Hooks agrupa os passos executados antes e depois da geração dos arquivos.
+type Hooks struct {
+	PreRender  []HookStep `yaml:"pre_render,omitempty" json:"pre_render,omitempty"`
+	PostRender []HookStep `yaml:"post_render,omitempty" json:"post_render,omitempty"`
+}
+
+// HookStep descreve um passo de hook. Dir, Command e os valores de Env aceitam
+// a mesma sintaxe de template dos arquivos.
+type HookStep struct {
+	Name    string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Action  HookAction        `yaml:"action" json:"action"`
+	Command []string          `yaml:"command,omitempty" json:"command,omitempty"`
+	Dir     string            `yaml:"dir,omitempty" json:"dir,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	Timeout time.Duration     `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Optional faz com que uma falha apenas gere aviso em vez de interromper a geração.
+	Optional bool   `yaml:"optional,omitempty" json:"optional,omitempty"`
+	When     string `yaml:"when,omitempty" json:"when,omitempty"`
+	// Paths e Mode configuram chmod; Message é a mensagem do commit de git_init.
+	Paths   []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+	Mode    string   `yaml:"mode,omitempty" json:"mode,omitempty"`
+	Message string   `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// hookStepJSON serializa Timeout como em template.yaml ("30s", "2m"), e não em
+// nanossegundos.
+type hookStepJSON struct {
+	hookStep
+	Timeout string `json:"timeout,omitempty"`
+}
+
+type hookStep HookStep
+
+// MarshalJSON implementa json.Marshaler.
+func (s HookStep) MarshalJSON() ([]byte, error) {
+	out := hookStepJSON{hookStep: hookStep(s)}
+	if s.Timeout > 0 {
+		out.Timeout = formatDuration(s.Timeout)
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implementa json.Unmarshaler, aceitando o formato de MarshalJSON.
+func (s *HookStep) UnmarshalJSON(data []byte) error {
+	var in hookStepJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*s = HookStep(in.hookStep)
+	s.Timeout = 0
+	if in.Timeout != "" {
+		timeout, err := time.ParseDuration(in.Timeout)
+		if err != nil {
+			return fmt.Errorf("hook timeout: %w", err)
+		}
+		s.Timeout = timeout
+	}
+	return nil
+}
+
+// formatDuration omite as unidades zeradas no fim de time.Duration.String, de
+// modo que 2m é "2m" e não "2m0s".
+func formatDuration(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// SkippedTemplate identifica um template omitido da listagem porque seu
+// template.yaml não pôde ser lido.
+type SkippedTemplate struct {
+	Name   string `yaml:"name" json:"name"`
+	Reason string `yaml:"reason" json:"reason"`
+}
+
+// PartialListError acompanha o resultado de ListTemplates quando algum template
+// foi omitido; os demais continuam na listagem.
+type PartialListError struct {
+	Skipped []SkippedTemplate
+}
+
+func (e *PartialListError) Error() string {
+	names := make([]string, len(e.Skipped))
+	for i, skipped := range e.Skipped {
+		names[i] = skipped.Name
+	}
+	return fmt.Sprintf("%d template(s) skipped due to invalid metadata: %s", len(e.Skipped), strings.Join(names, ", "))
+}
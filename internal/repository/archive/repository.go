@@ -0,0 +1,260 @@
+// Package archive carrega templates a partir de arquivos .tar.gz, .tgz ou .zip,
+// locais ou via HTTP, extraídos para um cache local.
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/repository/fs"
+)
+
+// Repository expõe os templates contidos em um arquivo compactado. O download e a
+// extração acontecem no primeiro acesso; o conteúdo é reaproveitado do cache
+// enquanto o hash do arquivo não mudar.
+type Repository struct {
+	source   string
+	subdir   string
+	cacheDir string
+	client   *http.Client
+
+	mu   sync.Mutex
+	repo *fs.Repository
+}
+
+// New cria um repositório para source (caminho local, file:// ou http(s)://).
+// subdir opcional aponta o diretório, dentro do arquivo, que contém os templates.
+func New(source, subdir, cacheDir string) *Repository {
+	return &Repository{
+		source:   source,
+		subdir:   subdir,
+		cacheDir: cacheDir,
+		client:   http.DefaultClient,
+	}
+}
+
+// IsArchive indica se o caminho ou URL aponta para um formato suportado.
+func IsArchive(source string) bool {
+	return archiveFormat(source) != ""
+}
+
+// ListTemplates lista os templates do arquivo.
+func (r *Repository) ListTemplates(ctx context.Context) ([]models.TemplateMetadata, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repo.ListTemplates(ctx)
+}
+
+// LoadTemplate carrega os metadados e o caminho extraído do template.
+func (r *Repository) LoadTemplate(ctx context.Context, name string) (*models.TemplateMetadata, string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return repo.LoadTemplate(ctx, name)
+}
+
+// LoadTemplateVersion carrega uma versão específica do template.
+func (r *Repository) LoadTemplateVersion(ctx context.Context, name, version string) (*models.TemplateMetadata, string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return repo.LoadTemplateVersion(ctx, name, version)
+}
+
+// ListVersions lista as versões do template disponíveis no arquivo.
+func (r *Repository) ListVersions(ctx context.Context, name string) ([]string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repo.ListVersions(ctx, name)
+}
+
+func (r *Repository) open(ctx context.Context) (*fs.Repository, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.repo != nil {
+		return r.repo, nil
+	}
+
+	format := archiveFormat(r.source)
+	if format == "" {
+		return nil, fmt.Errorf("unsupported archive format: %s", r.source)
+	}
+	if err := os.MkdirAll(r.cacheDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+
+	path, cleanup, err := r.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	sum, err := hashFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(r.cacheDir, "archive-"+sum[:16])
+	err = ExtractOnce(dir, func(tmp string) error {
+		if format == ".zip" {
+			return ExtractZip(path, tmp)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open archive: %w", err)
+		}
+		defer f.Close()
+		return ExtractTarGz(f, tmp)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := ResolveRoot(dir, r.subdir)
+	if err != nil {
+		return nil, err
+	}
+	r.repo = fs.New(root)
+	return r.repo, nil
+}
+
+// fetch devolve um caminho local para o arquivo, baixando-o quando necessário.
+func (r *Repository) fetch(ctx context.Context) (string, func(), error) {
+	if !isRemote(r.source) {
+		return strings.TrimPrefix(r.source, "file://"), func() {}, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.source, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("build archive request: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("download archive: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("download archive: unexpected status %s", resp.Status)
+	}
+
+	tmp, err := os.CreateTemp(r.cacheDir, "download-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("create download file: %w", err)
+	}
+	cleanup := func() { os.Remove(tmp.Name()) }
+	_, err = io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("download archive: %w", err)
+	}
+	return tmp.Name(), cleanup, nil
+}
+
+// ExtractOnce popula dir com extract apenas se ele ainda não existir. A extração
+// ocorre em um diretório temporário renomeado ao final, então um cache parcial
+// nunca é reaproveitado.
+func ExtractOnce(dir string, extract func(tmp string) error) error {
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return nil
+	}
+
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".extract-*")
+	if err != nil {
+		return fmt.Errorf("create extraction dir: %w", err)
+	}
+	if err := extract(tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		os.RemoveAll(tmp)
+		// Outro processo pode ter concluído a mesma extração.
+		if info, statErr := os.Stat(dir); statErr == nil && info.IsDir() {
+			return nil
+		}
+		return fmt.Errorf("store extracted templates: %w", err)
+	}
+	return nil
+}
+
+// ResolveRoot localiza o diretório de templates dentro do conteúdo extraído. Sem
+// subdir, um único diretório de topo sem template.yaml (como nos arquivos gerados
+// por forges de git) é tratado como invólucro e ignorado.
+func ResolveRoot(dir, subdir string) (string, error) {
+	if subdir != "" {
+		root, err := safeJoin(dir, subdir)
+		if err != nil {
+			return "", err
+		}
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("subdir not found in templates source: %s", subdir)
+		}
+		return root, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("read extracted templates: %w", err)
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		wrapper := filepath.Join(dir, entries[0].Name())
+		if _, err := os.Stat(filepath.Join(wrapper, "template.yaml")); errors.Is(err, os.ErrNotExist) {
+			return wrapper, nil
+		}
+	}
+	return dir, nil
+}
+
+func archiveFormat(source string) string {
+	name := source
+	if u, err := url.Parse(source); err == nil && u.Scheme != "" && len(u.Scheme) > 1 {
+		name = u.Path
+	}
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ".tar.gz"
+	case strings.HasSuffix(lower, ".zip"):
+		return ".zip"
+	default:
+		return ""
+	}
+}
+
+func isRemote(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open archive: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hash archive: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
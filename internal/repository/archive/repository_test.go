@@ -0,0 +1,117 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func zipBytes(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestRepositoryTarGzWithWrapperDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "templates.tar.gz")
+	require.NoError(t, os.WriteFile(path, tarGz(t, map[string]string{
+		"templates-1.2.0/demo/template.yaml": "name: demo\nversion: \"1.2.0\"\n",
+		"templates-1.2.0/demo/main.go.tmpl":  "package main\n",
+	}), 0o644))
+
+	repo := New(path, "", filepath.Join(dir, "cache"))
+	templates, err := repo.ListTemplates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.Equal(t, "1.2.0", templates[0].Version)
+
+	_, templatePath, err := repo.LoadTemplate(context.Background(), "demo")
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(templatePath, "main.go.tmpl"))
+}
+
+func TestRepositoryZipOverHTTPWithSubdir(t *testing.T) {
+	t.Parallel()
+
+	data := zipBytes(t, map[string]string{
+		"repo/templates/demo/template.yaml": "name: demo\n",
+		"repo/README.md":                    "readme\n",
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	repo := New(server.URL+"/templates.zip", "repo/templates", t.TempDir())
+	meta, _, err := repo.LoadTemplate(context.Background(), "demo")
+	require.NoError(t, err)
+	require.Equal(t, "demo", meta.Name)
+}
+
+func TestRepositoryHTTPError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	repo := New(server.URL+"/templates.tgz", "", t.TempDir())
+	_, err := repo.ListTemplates(context.Background())
+	require.ErrorContains(t, err, "unexpected status")
+}
+
+func TestExtractRejectsPathTraversal(t *testing.T) {
+	t.Parallel()
+
+	data := tarGz(t, map[string]string{"../evil.txt": "x"})
+	err := ExtractTarGz(bytes.NewReader(data), t.TempDir())
+	require.ErrorContains(t, err, "escapes destination")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "evil.zip")
+	require.NoError(t, os.WriteFile(path, zipBytes(t, map[string]string{"a/../../evil.txt": "x"}), 0o644))
+	require.ErrorContains(t, ExtractZip(path, t.TempDir()), "escapes destination")
+}
+
+func TestIsArchive(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsArchive("templates.tar.gz"))
+	require.True(t, IsArchive("https://example.com/t.TGZ?token=1"))
+	require.True(t, IsArchive("file:///srv/templates.zip"))
+	require.False(t, IsArchive("templates"))
+}
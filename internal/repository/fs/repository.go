@@ -6,16 +6,21 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/sony/gobreaker"
 	"gopkg.in/yaml.v3"
 
 	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/pkg/semver"
 )
 
 const metadataFile = "template.yaml"
 
+// versionSeparator separa nome e versão nos diretórios de versões arquivadas.
+const versionSeparator = "@"
+
 // Repository provê acesso aos templates armazenados no filesystem.
 type Repository struct {
 	root    string
@@ -40,23 +45,29 @@ func New(root string) *Repository {
 	}
 }
 
-// ListTemplates lista os templates disponíveis a partir dos metadados.
+// ListTemplates lista os templates disponíveis a partir dos metadados. Templates
+// cujo template.yaml não pode ser lido são omitidos e reportados em um
+// *models.PartialListError, retornado junto com os demais.
 func (r *Repository) ListTemplates(ctx context.Context) ([]models.TemplateMetadata, error) {
+	type listing struct {
+		templates []models.TemplateMetadata
+		skipped   []models.SkippedTemplate
+	}
 	result, err := r.breaker.Execute(func() (interface{}, error) {
 		entries, err := os.ReadDir(r.root)
 		if err != nil {
 			return nil, fmt.Errorf("read templates dir: %w", err)
 		}
 
-		templates := make([]models.TemplateMetadata, 0, len(entries))
+		list := listing{templates: make([]models.TemplateMetadata, 0, len(entries))}
 		for _, entry := range entries {
-			if !entry.IsDir() {
+			if !entry.IsDir() || !listable(entry.Name()) {
 				continue
 			}
-			path := filepath.Join(r.root, entry.Name(), metadataFile)
-			meta, err := readMetadata(path)
+			meta, err := r.listMetadata(entry.Name())
 			if err != nil {
-				return nil, err
+				list.skipped = append(list.skipped, models.SkippedTemplate{Name: entry.Name(), Reason: err.Error()})
+				continue
 			}
 			if meta.Name == "" {
 				meta.Name = entry.Name()
@@ -64,21 +75,205 @@ func (r *Repository) ListTemplates(ctx context.Context) ([]models.TemplateMetada
 			if meta.DisplayName == "" {
 				meta.DisplayName = entry.Name()
 			}
-			templates = append(templates, *meta)
+			list.templates = append(list.templates, *meta)
 		}
 
-		return templates, nil
+		return list, nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	return result.([]models.TemplateMetadata), nil
+	list := result.(listing)
+	if len(list.skipped) > 0 {
+		return list.templates, &models.PartialListError{Skipped: list.skipped}
+	}
+	return list.templates, nil
 }
 
-// LoadTemplate carrega os metadados e caminho do template solicitado.
+// LoadTemplate carrega os metadados e caminho do template solicitado. Um
+// template organizado em subdiretórios de versão é carregado na mais recente.
 func (r *Repository) LoadTemplate(ctx context.Context, name string) (*models.TemplateMetadata, string, error) {
+	nested, err := r.nestedVersions(name)
+	if err != nil {
+		return nil, "", err
+	}
+	if latest, ok := latestVersion(nested); ok {
+		return r.loadVersion(name, latest)
+	}
+	return r.load(name, name)
+}
+
+// LoadTemplateVersion carrega uma versão específica do template, procurada nos
+// layouts descritos em ListVersions. Versão vazia equivale a LoadTemplate.
+func (r *Repository) LoadTemplateVersion(ctx context.Context, name, version string) (*models.TemplateMetadata, string, error) {
+	if version == "" {
+		return r.LoadTemplate(ctx, name)
+	}
+
+	versions, err := r.versions(name)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, candidate := range versions {
+		if semver.Equal(candidate.version, version) {
+			return r.loadVersion(name, candidate)
+		}
+	}
+
+	if _, _, err := r.LoadTemplate(ctx, name); err != nil {
+		return nil, "", err
+	}
+	return nil, "", fmt.Errorf("template %s version %s not found", name, version)
+}
+
+// ListVersions lista as versões disponíveis de name, sem ordem definida. Versões
+// podem ficar em diretórios irmãos <nome>@<versão>, com o diretório principal
+// correspondendo à versão declarada em seu template.yaml, ou em subdiretórios
+// <nome>/<versão>/ quando o diretório principal não tem template.yaml.
+func (r *Repository) ListVersions(ctx context.Context, name string) ([]string, error) {
+	versions, err := r.versions(name)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, len(versions))
+	for i, version := range versions {
+		result[i] = version.version
+	}
+	return result, nil
+}
+
+// templateVersion é uma versão de um template e o diretório, relativo à raiz,
+// que a contém.
+type templateVersion struct {
+	version string
+	dir     string
+}
+
+func (r *Repository) versions(name string) ([]templateVersion, error) {
+	var versions []templateVersion
+	seen := make(map[string]bool)
+	add := func(version, dir string) {
+		if version != "" && !seen[version] {
+			seen[version] = true
+			versions = append(versions, templateVersion{version: version, dir: dir})
+		}
+	}
+
+	nested, err := r.nestedVersions(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range nested {
+		add(version.version, version.dir)
+	}
+	if len(nested) == 0 {
+		if info, err := os.Stat(filepath.Join(r.root, name)); err == nil && info.IsDir() {
+			meta, err := readMetadata(filepath.Join(r.root, name, metadataFile))
+			if err != nil {
+				return nil, err
+			}
+			add(meta.Version, name)
+		}
+	}
+
+	entries, err := os.ReadDir(r.root)
+	if err != nil {
+		return nil, fmt.Errorf("read templates dir: %w", err)
+	}
+	prefix := name + versionSeparator
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+			add(strings.TrimPrefix(entry.Name(), prefix), entry.Name())
+		}
+	}
+	return versions, nil
+}
+
+// nestedVersions lista os subdiretórios <nome>/<versão>/ de um template sem
+// template.yaml próprio. Só contam subdiretórios cujo nome é uma versão semver
+// e que têm template.yaml.
+func (r *Repository) nestedVersions(name string) ([]templateVersion, error) {
+	dir := filepath.Join(r.root, name)
+	if _, err := os.Stat(filepath.Join(dir, metadataFile)); err == nil {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read template dir: %w", err)
+	}
+
+	var versions []templateVersion
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := semver.Parse(entry.Name()); err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.Name(), metadataFile)); err != nil {
+			continue
+		}
+		versions = append(versions, templateVersion{version: entry.Name(), dir: filepath.Join(name, entry.Name())})
+	}
+	return versions, nil
+}
+
+// listMetadata lê os metadados exibidos por ListTemplates para o diretório
+// name, usando a versão mais recente quando há subdiretórios de versão.
+func (r *Repository) listMetadata(name string) (*models.TemplateMetadata, error) {
+	nested, err := r.nestedVersions(name)
+	if err != nil {
+		return nil, err
+	}
+	latest, ok := latestVersion(nested)
+	if !ok {
+		return readMetadata(filepath.Join(r.root, name, metadataFile))
+	}
+	meta, err := readMetadata(filepath.Join(r.root, latest.dir, metadataFile))
+	if err != nil {
+		return nil, err
+	}
+	if meta.Version == "" {
+		meta.Version = latest.version
+	}
+	return meta, nil
+}
+
+func (r *Repository) loadVersion(name string, version templateVersion) (*models.TemplateMetadata, string, error) {
+	meta, path, err := r.load(name, version.dir)
+	if err != nil {
+		return nil, "", err
+	}
+	if meta.Version == "" {
+		meta.Version = version.version
+	}
+	return meta, path, nil
+}
+
+// latestVersion escolhe, como semver.Latest, a versão mais recente.
+func latestVersion(versions []templateVersion) (templateVersion, bool) {
+	names := make([]string, len(versions))
+	for i, version := range versions {
+		names[i] = version.version
+	}
+	latest, ok := semver.Latest(names, nil)
+	if !ok {
+		return templateVersion{}, false
+	}
+	for _, version := range versions {
+		if version.version == latest {
+			return version, true
+		}
+	}
+	return templateVersion{}, false
+}
+
+func (r *Repository) load(name, dir string) (*models.TemplateMetadata, string, error) {
 	res, err := r.breaker.Execute(func() (interface{}, error) {
-		path := filepath.Join(r.root, name)
+		path := filepath.Join(r.root, dir)
 		info, err := os.Stat(path)
 		if err != nil {
 			if errors.Is(err, os.ErrNotExist) {
@@ -119,6 +314,13 @@ func (r *Repository) LoadTemplate(ctx context.Context, name string) (*models.Tem
 	return result.meta, result.path, nil
 }
 
+// listable indica se o diretório é um template exibido por ListTemplates. Versões
+// arquivadas (nome@versão) e diretórios iniciados por "_" ou "." — bases abstratas
+// e partials compartilhados — continuam acessíveis por LoadTemplate.
+func listable(name string) bool {
+	return !strings.Contains(name, versionSeparator) && !strings.HasPrefix(name, "_") && !strings.HasPrefix(name, ".")
+}
+
 func readMetadata(path string) (*models.TemplateMetadata, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
 )
 
 func TestRepositoryListAndLoad(t *testing.T) {
@@ -55,3 +57,101 @@ func TestRepositoryListWithoutMetadata(t *testing.T) {
 	require.Equal(t, "empty", templates[0].Name)
 	require.Empty(t, templates[0].Description)
 }
+
+func TestRepositoryListSkipsInvalidMetadata(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"demo":   "name: demo\n",
+		"broken": "name: [broken\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, name), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name, "template.yaml"), []byte(content), 0o644))
+	}
+
+	templates, err := New(root).ListTemplates(context.Background())
+	var partial *models.PartialListError
+	require.ErrorAs(t, err, &partial)
+	require.Len(t, partial.Skipped, 1)
+	require.Equal(t, "broken", partial.Skipped[0].Name)
+	require.Contains(t, partial.Skipped[0].Reason, "unmarshal metadata")
+	require.Len(t, templates, 1)
+	require.Equal(t, "demo", templates[0].Name)
+}
+
+func TestRepositoryLoadTemplateVersion(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "demo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "demo", "template.yaml"), []byte("version: \"2.0.0\"\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "demo@1.0.0"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "demo@1.0.0", "template.yaml"), []byte("version: \"1.0.0\"\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "_base"), 0o755))
+
+	repo := New(root)
+
+	results, err := repo.ListTemplates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	meta, path, err := repo.LoadTemplateVersion(context.Background(), "demo", "1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "demo", meta.Name)
+	require.Equal(t, filepath.Join(root, "demo@1.0.0"), path)
+
+	meta, path, err = repo.LoadTemplateVersion(context.Background(), "demo", "2.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", meta.Version)
+	require.Equal(t, filepath.Join(root, "demo"), path)
+
+	_, _, err = repo.LoadTemplateVersion(context.Background(), "demo", "3.0.0")
+	require.ErrorContains(t, err, "version 3.0.0 not found")
+
+	versions, err := repo.ListVersions(context.Background(), "demo")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"2.0.0", "1.0.0"}, versions)
+}
+
+func TestRepositoryNestedVersions(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for version, content := range map[string]string{
+		"1.0.0":      "description: v1\n",
+		"1.1.0":      "description: v1.1\n",
+		"2.0.0-rc.1": "description: next\n",
+	} {
+		dir := filepath.Join(root, "demo", version)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "template.yaml"), []byte(content), 0o644))
+	}
+	// Diretórios sem template.yaml ou com nome que não é versão são ignorados.
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "demo", "3.0.0"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "demo", "shared"), 0o755))
+
+	repo := New(root)
+
+	versions, err := repo.ListVersions(context.Background(), "demo")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"1.0.0", "1.1.0", "2.0.0-rc.1"}, versions)
+
+	results, err := repo.ListTemplates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "1.1.0", results[0].Version, "listagem mostra a versão estável mais recente")
+	require.Equal(t, "v1.1", results[0].Description)
+
+	meta, path, err := repo.LoadTemplate(context.Background(), "demo")
+	require.NoError(t, err)
+	require.Equal(t, "demo", meta.Name)
+	require.Equal(t, "1.1.0", meta.Version)
+	require.Equal(t, filepath.Join(root, "demo", "1.1.0"), path)
+
+	meta, path, err = repo.LoadTemplateVersion(context.Background(), "demo", "v2.0.0-rc.1")
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0-rc.1", meta.Version)
+	require.Equal(t, filepath.Join(root, "demo", "2.0.0-rc.1"), path)
+}
+
@@ -0,0 +1,310 @@
+// Package git carrega templates de um repositório git em uma ref específica,
+// mantendo um clone espelho local como cache.
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/repository/archive"
+	"github.com/vertikon/mcp-ultra-templates/internal/repository/fs"
+	"github.com/vertikon/mcp-ultra-templates/pkg/semver"
+)
+
+// defaultRef é usada quando a URI não informa ref.
+const defaultRef = "HEAD"
+
+// commitPattern reconhece refs que são hashes de commit, abreviados ou não.
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{7,64}$`)
+
+// Repository expõe os templates de um repositório git. No primeiro acesso o clone
+// espelho é criado ou atualizado e a ref é extraída com git archive para um
+// diretório identificado pelo commit, reaproveitado nas execuções seguintes.
+//
+// Além das versões presentes na ref, cada tag <nome>@<versão> ou
+// <nome>/<versão> (com ou sem prefixo "v") publica uma versão do template,
+// extraída sob demanda.
+type Repository struct {
+	remote   string
+	ref      string
+	subdir   string
+	cacheDir string
+
+	mu       sync.Mutex
+	mirror   string
+	fetchErr error
+	repos    map[string]*fs.Repository
+	stale    func(ref string, err error)
+}
+
+// New cria um repositório para remote (qualquer URL aceita por git clone) na ref
+// informada. subdir opcional aponta o diretório que contém os templates.
+func New(remote, ref, subdir, cacheDir string) *Repository {
+	if ref == "" {
+		ref = defaultRef
+	}
+	return &Repository{
+		remote:   remote,
+		ref:      ref,
+		subdir:   subdir,
+		cacheDir: cacheDir,
+	}
+}
+
+// OnStaleCache registra fn, chamada quando a busca no remoto falha e uma tag ou
+// commit é resolvido a partir do cache local. Branches não usam o cache nesse
+// caso: a busca falha com erro.
+func (r *Repository) OnStaleCache(fn func(ref string, err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stale = fn
+}
+
+// ListTemplates lista os templates na ref configurada.
+func (r *Repository) ListTemplates(ctx context.Context) ([]models.TemplateMetadata, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return repo.ListTemplates(ctx)
+}
+
+// LoadTemplate carrega os metadados e o caminho extraído do template.
+func (r *Repository) LoadTemplate(ctx context.Context, name string) (*models.TemplateMetadata, string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return repo.LoadTemplate(ctx, name)
+}
+
+// LoadTemplateVersion carrega uma versão específica do template, procurando
+// primeiro na ref configurada e depois nas tags do template.
+func (r *Repository) LoadTemplateVersion(ctx context.Context, name, version string) (*models.TemplateMetadata, string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	meta, path, err := repo.LoadTemplateVersion(ctx, name, version)
+	if err == nil || version == "" {
+		return meta, path, err
+	}
+
+	tags, tagErr := r.tagVersions(ctx, name)
+	if tagErr != nil {
+		return nil, "", tagErr
+	}
+	for tagVersion, tag := range tags {
+		if c, cmpErr := semver.Compare(tagVersion, version); cmpErr != nil || c != 0 {
+			continue
+		}
+		tagRepo, err := r.checkout(ctx, tag)
+		if err != nil {
+			return nil, "", err
+		}
+		meta, path, err := tagRepo.LoadTemplate(ctx, name)
+		if err != nil {
+			return nil, "", fmt.Errorf("load template %s from tag %s: %w", name, tag, err)
+		}
+		if meta.Version == "" {
+			meta.Version = version
+		}
+		return meta, path, nil
+	}
+	return nil, "", err
+}
+
+// ListVersions lista as versões do template na ref configurada e nas tags.
+func (r *Repository) ListVersions(ctx context.Context, name string) ([]string, error) {
+	repo, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := repo.ListVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := r.tagVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		if v, err := semver.Parse(version); err == nil {
+			seen[v.String()] = true
+		}
+	}
+	for version := range tags {
+		if !seen[version] {
+			versions = append(versions, version)
+		}
+	}
+	return versions, nil
+}
+
+// tagVersions mapeia cada versão de name publicada em tag, normalizada sem o
+// prefixo "v", para a tag correspondente.
+func (r *Repository) tagVersions(ctx context.Context, name string) (map[string]string, error) {
+	if _, err := r.open(ctx); err != nil {
+		return nil, err
+	}
+	out, err := runGit(ctx, nil, "--git-dir", r.mirror, "tag", "--list")
+	if err != nil {
+		return nil, fmt.Errorf("list git tags: %w", err)
+	}
+
+	versions := make(map[string]string)
+	for _, tag := range strings.Fields(out) {
+		for _, separator := range []string{"@", "/"} {
+			version, ok := strings.CutPrefix(tag, name+separator)
+			if !ok {
+				continue
+			}
+			if v, err := semver.Parse(version); err == nil {
+				versions[v.String()] = tag
+			}
+		}
+	}
+	return versions, nil
+}
+
+func (r *Repository) open(ctx context.Context) (*fs.Repository, error) {
+	return r.checkout(ctx, r.ref)
+}
+
+// checkout extrai ref, uma vez por instância, e retorna os templates dela. O
+// clone espelho é sincronizado no primeiro acesso; se a sincronização falhar,
+// tags e commits já presentes no cache continuam disponíveis, mas branches,
+// que podem ter avançado no remoto, não.
+func (r *Repository) checkout(ctx context.Context, ref string) (*fs.Repository, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if repo, ok := r.repos[ref]; ok {
+		return repo, nil
+	}
+
+	key := hashString(r.remote)[:16]
+	if r.mirror == "" {
+		if err := os.MkdirAll(r.cacheDir, 0o755); err != nil {
+			return nil, fmt.Errorf("create cache dir: %w", err)
+		}
+		r.mirror = filepath.Join(r.cacheDir, "git-"+key+".git")
+		r.fetchErr = r.sync(ctx, r.mirror)
+	}
+
+	commit, err := runGit(ctx, nil, "--git-dir", r.mirror, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		if r.fetchErr != nil {
+			return nil, r.fetchErr
+		}
+		return nil, fmt.Errorf("resolve git ref %s: %w", ref, err)
+	}
+	commit = strings.TrimSpace(commit)
+	if r.fetchErr != nil {
+		if !r.pinned(ctx, ref, commit) {
+			return nil, fmt.Errorf("git ref %s may be stale in the cache: %w", ref, r.fetchErr)
+		}
+		if r.stale != nil {
+			r.stale(ref, r.fetchErr)
+		}
+	}
+
+	dir := filepath.Join(r.cacheDir, "git-"+key+"-"+commit)
+	err = archive.ExtractOnce(dir, func(tmp string) error {
+		return r.export(ctx, r.mirror, ref, commit, tmp)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := archive.ResolveRoot(dir, r.subdir)
+	if err != nil {
+		return nil, err
+	}
+	if r.repos == nil {
+		r.repos = make(map[string]*fs.Repository)
+	}
+	r.repos[ref] = fs.New(root)
+	return r.repos[ref], nil
+}
+
+// pinned informa se ref aponta sempre para o mesmo commit: uma tag ou o próprio
+// hash do commit.
+func (r *Repository) pinned(ctx context.Context, ref, commit string) bool {
+	if commitPattern.MatchString(ref) && strings.HasPrefix(commit, ref) {
+		return true
+	}
+	_, err := runGit(ctx, nil, "--git-dir", r.mirror, "rev-parse", "--verify", "--quiet", "refs/tags/"+ref)
+	return err == nil
+}
+
+// sync cria o clone espelho ou busca atualizações. Uma falha na busca com cache
+// existente é devolvida, mas a ref ainda pode ser resolvida a partir do cache.
+func (r *Repository) sync(ctx context.Context, mirror string) error {
+	if _, err := os.Stat(mirror); err == nil {
+		if _, err := runGit(ctx, nil, "--git-dir", mirror, "remote", "update", "--prune"); err != nil {
+			return fmt.Errorf("fetch templates repository: %w", err)
+		}
+		return nil
+	}
+
+	tmp, err := os.MkdirTemp(r.cacheDir, ".clone-*")
+	if err != nil {
+		return fmt.Errorf("create clone dir: %w", err)
+	}
+	if _, err := runGit(ctx, nil, "clone", "--mirror", "--quiet", r.remote, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return fmt.Errorf("clone templates repository: %w", err)
+	}
+	if err := os.Rename(tmp, mirror); err != nil {
+		os.RemoveAll(tmp)
+		return fmt.Errorf("store templates repository: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) export(ctx context.Context, mirror, ref, commit, dst string) error {
+	var out bytes.Buffer
+	if _, err := runGit(ctx, &out, "--git-dir", mirror, "archive", "--format=tar", commit); err != nil {
+		return fmt.Errorf("export git ref %s: %w", ref, err)
+	}
+	return archive.ExtractTar(&out, dst)
+}
+
+// runGit executa git sem prompts interativos. Quando stdout é informado a saída
+// é escrita nele; caso contrário é devolvida como string.
+func runGit(ctx context.Context, stdout io.Writer, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var buf, stderr bytes.Buffer
+	cmd.Stdout = &buf
+	if stdout != nil {
+		cmd.Stdout = stdout
+	}
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git: %s", msg)
+		}
+		return "", fmt.Errorf("git: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func setupRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "--quiet")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates", "demo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "demo", "template.yaml"), []byte("version: \"1.0.0\"\n"), 0o644))
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "--quiet", "-m", "v1")
+	gitCmd(t, dir, "tag", "v1.0.0")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "demo", "template.yaml"), []byte("version: \"2.0.0\"\n"), 0o644))
+	gitCmd(t, dir, "commit", "--quiet", "-am", "v2")
+	return dir
+}
+
+func TestRepositoryLoadsRef(t *testing.T) {
+	t.Parallel()
+
+	remote := setupRemote(t)
+	cacheDir := t.TempDir()
+
+	repo := New("file://"+remote, "v1.0.0", "templates", cacheDir)
+	meta, path, err := repo.LoadTemplate(context.Background(), "demo")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", meta.Version)
+	require.DirExists(t, path)
+
+	head := New("file://"+remote, "", "templates", cacheDir)
+	meta, _, err = head.LoadTemplate(context.Background(), "demo")
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", meta.Version)
+}
+
+func TestRepositoryTagVersions(t *testing.T) {
+	t.Parallel()
+
+	remote := setupRemote(t)
+	gitCmd(t, remote, "tag", "demo@1.0.0", "v1.0.0")
+	gitCmd(t, remote, "tag", "other/v3.0.0", "v1.0.0")
+
+	repo := New("file://"+remote, "", "templates", t.TempDir())
+	versions, err := repo.ListVersions(context.Background(), "demo")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"2.0.0", "1.0.0"}, versions)
+
+	meta, path, err := repo.LoadTemplateVersion(context.Background(), "demo", "1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", meta.Version)
+	require.DirExists(t, path)
+
+	meta, _, err = repo.LoadTemplate(context.Background(), "demo")
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", meta.Version)
+
+	_, _, err = repo.LoadTemplateVersion(context.Background(), "demo", "3.0.0")
+	require.ErrorContains(t, err, "version 3.0.0 not found")
+}
+
+func TestRepositoryUnknownRef(t *testing.T) {
+	t.Parallel()
+
+	remote := setupRemote(t)
+	repo := New("file://"+remote, "v9.9.9", "", t.TempDir())
+	_, err := repo.ListTemplates(context.Background())
+	require.ErrorContains(t, err, "resolve git ref v9.9.9")
+}
+
+func TestRepositoryFetchFailure(t *testing.T) {
+	t.Parallel()
+
+	remote := setupRemote(t)
+	cacheDir := t.TempDir()
+	_, err := New("file://"+remote, "v1.0.0", "templates", cacheDir).ListTemplates(context.Background())
+	require.NoError(t, err)
+	out, err := exec.Command("git", "-C", remote, "rev-parse", "v1.0.0^{commit}").Output()
+	require.NoError(t, err)
+	commit := strings.TrimSpace(string(out))
+
+	// Sem o remoto, a busca falha e apenas o cache está disponível.
+	require.NoError(t, os.Rename(remote, remote+"-offline"))
+
+	for _, ref := range []string{"v1.0.0", commit[:12]} {
+		repo := New("file://"+remote, ref, "templates", cacheDir)
+		var warned []string
+		repo.OnStaleCache(func(ref string, err error) {
+			require.ErrorContains(t, err, "fetch templates repository")
+			warned = append(warned, ref)
+		})
+		meta, _, err := repo.LoadTemplate(context.Background(), "demo")
+		require.NoError(t, err, ref)
+		require.Equal(t, "1.0.0", meta.Version)
+		require.Equal(t, []string{ref}, warned)
+	}
+
+	_, err = New("file://"+remote, "", "templates", cacheDir).ListTemplates(context.Background())
+	require.ErrorContains(t, err, "git ref HEAD may be stale in the cache")
+	require.ErrorContains(t, err, "fetch templates repository")
+}
@@ -0,0 +1,106 @@
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// SourceComputed identifica valores derivados da seção computed.
+const SourceComputed = "computed"
+
+// computeValues avalia as variáveis computadas sobre values, em ordem de
+// dependência, e retorna as chaves efetivamente calculadas. Uma chave que já
+// possui valor (por defaults, perfil ou entrada do usuário) não é recalculada.
+func computeValues(meta *models.TemplateMetadata, values map[string]any) ([]string, error) {
+	order, err := computedOrder(meta.Computed)
+	if err != nil {
+		return nil, err
+	}
+
+	var computed []string
+	for _, key := range order {
+		if _, ok := valuespkg.Lookup(values, key); ok {
+			continue
+		}
+		result, err := pkgtemplate.RenderString(key, meta.Computed[key], values)
+		if err != nil {
+			return nil, fmt.Errorf("computed variable %s: %w", key, err)
+		}
+		if err := valuespkg.Set(values, key, result); err != nil {
+			return nil, fmt.Errorf("computed variable %s: %w", key, err)
+		}
+		computed = append(computed, key)
+	}
+	return computed, nil
+}
+
+// computedOrder ordena as variáveis computadas de forma que cada uma venha
+// depois das que referencia. Chaves independentes seguem a ordem alfabética.
+func computedOrder(computed map[string]string) ([]string, error) {
+	keys := make([]string, 0, len(computed))
+	for key := range computed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	deps := make(map[string][]string, len(keys))
+	for _, key := range keys {
+		analysis := pkgtemplate.AnalyzeTemplate(key, computed[key])
+		if analysis.Err != nil {
+			return nil, fmt.Errorf("computed variable %s: line %d: %s", key, analysis.Err.Line, analysis.Err.Message)
+		}
+		refs := make(map[string]bool)
+		for _, ref := range analysis.Variables {
+			refs[ref.Name] = true
+		}
+		for _, other := range keys {
+			if isDeclared(refs, other) {
+				deps[key] = append(deps[key], other)
+			}
+		}
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(keys))
+	order := make([]string, 0, len(keys))
+	var stack []string
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for stack[start] != key {
+				start++
+			}
+			cycle := append(append([]string(nil), stack[start:]...), key)
+			return fmt.Errorf("computed variables form a cycle: %s", strings.Join(cycle, " -> "))
+		}
+		state[key] = visiting
+		stack = append(stack, key)
+		for _, dep := range deps[key] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[key] = done
+		order = append(order, key)
+		return nil
+	}
+	for _, key := range keys {
+		if err := visit(key); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
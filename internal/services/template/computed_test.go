@@ -0,0 +1,134 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+func TestComputedOrder(t *testing.T) {
+	t.Parallel()
+
+	order, err := computedOrder(map[string]string{
+		"image":       "{{ .registry }}/{{ .binary_name }}",
+		"binary_name": "{{ kebab .service_name }}",
+		"registry":    "ghcr.io/{{ .owner }}",
+		"go_package":  "{{ snake .service_name }}",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"binary_name", "go_package", "registry", "image"}, order)
+
+	_, err = computedOrder(map[string]string{
+		"a": "{{ .b }}",
+		"b": "{{ .c }}",
+		"c": "{{ .a }}-x",
+	})
+	require.EqualError(t, err, "computed variables form a cycle: a -> b -> c -> a")
+
+	_, err = computedOrder(map[string]string{"a": "{{ .b "})
+	require.ErrorContains(t, err, "computed variable a")
+}
+
+func TestComputeValues(t *testing.T) {
+	t.Parallel()
+
+	meta := &models.TemplateMetadata{Computed: map[string]string{
+		"binary_name":    "{{ kebab .service_name }}",
+		"go_package":     "{{ snake .service_name }}",
+		"names.exported": "{{ camel .binary_name }}",
+	}}
+	values := map[string]any{"service_name": "Order Service", "go_package": "custom"}
+
+	computed, err := computeValues(meta, values)
+	require.NoError(t, err)
+	require.Equal(t, []string{"binary_name", "names.exported"}, computed)
+	require.Equal(t, map[string]any{
+		"service_name": "Order Service",
+		"binary_name":  "order-service",
+		"go_package":   "custom",
+		"names":        map[string]any{"exported": "OrderService"},
+	}, values)
+
+	_, err = computeValues(&models.TemplateMetadata{Computed: map[string]string{"x": "{{ .missing }}"}}, map[string]any{})
+	require.ErrorContains(t, err, "computed variable x")
+}
+
+func TestServiceRenderComputedVariables(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	templateDir := writeTemplateFiles(t, map[string]string{
+		"cmd/{{ .binary_name }}/main.go.tmpl": "package {{ .go_package }}\n",
+	})
+	meta := &models.TemplateMetadata{
+		Name:      "demo",
+		Variables: []models.TemplateVariable{{Key: "service_name", Required: true}, {Key: "go_package", Pattern: "[a-z_]+"}},
+		Computed: map[string]string{
+			"binary_name": "{{ kebab .service_name }}",
+			"go_package":  "{{ snake .service_name }}",
+		},
+	}
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(meta, templateDir, nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	outputDir := t.TempDir()
+	_, err := service.Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    outputDir,
+		Values:       map[string]any{"service_name": "Order Service"},
+		Overwrite:    true,
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "cmd", "order-service", "main.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package order_service\n", string(data))
+}
+
+func TestServiceRenderComputedAfterValidation(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	templateDir := writeTemplateFiles(t, map[string]string{"{{ .binary_name }}.txt": "{{ .port }}\n"})
+	meta := &models.TemplateMetadata{
+		Name: "demo",
+		Variables: []models.TemplateVariable{
+			{Key: "service_name", Required: true},
+			{Key: "port", Type: models.VariableTypeInt},
+		},
+		Computed: map[string]string{"binary_name": "{{ kebab .service_name }}"},
+	}
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(meta, templateDir, nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	_, err := service.Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    filepath.Join(t.TempDir(), "out"),
+		Values:       map[string]any{"port": "abc"},
+	})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Len(t, validationErr.Errors, 2)
+	require.ErrorContains(t, err, "service_name")
+	require.ErrorContains(t, err, "port")
+	require.NotContains(t, err.Error(), "computed variable")
+}
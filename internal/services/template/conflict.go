@@ -0,0 +1,381 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ConflictMode decide o que acontece com um arquivo gerado que já existe na
+// saída com conteúdo diferente.
+type ConflictMode string
+
+// Modos aceitos em RenderRequest.OnConflict. ConflictPrompt delega a decisão,
+// arquivo a arquivo, a RenderRequest.ResolveConflict.
+const (
+	ConflictSkip      ConflictMode = "skip"
+	ConflictOverwrite ConflictMode = "overwrite"
+	ConflictBackup    ConflictMode = "backup"
+	ConflictPrompt    ConflictMode = "prompt"
+	ConflictFail      ConflictMode = "fail"
+)
+
+// ConflictModes lista os modos na ordem exibida pela CLI.
+var ConflictModes = []ConflictMode{ConflictSkip, ConflictOverwrite, ConflictBackup, ConflictPrompt, ConflictFail}
+
+// ConflictResolver escolhe skip, overwrite ou backup para um arquivo em conflito.
+type ConflictResolver func(path string) (ConflictMode, error)
+
+// ConflictBackupDir agrupa, na raiz da saída, os backups feitos com
+// RenderRequest.BackupDir, em um subdiretório por renderização.
+const ConflictBackupDir = ".mcp-backups"
+
+// OrigSuffix é acrescentado aos arquivos preservados pelo modo backup.
+const OrigSuffix = ".orig"
+
+// ResolvedConflict registra a decisão tomada para um arquivo em conflito.
+type ResolvedConflict struct {
+	Path   string       `json:"path"`
+	Action ConflictMode `json:"action"`
+	// Backup é o caminho, relativo à saída, do conteúdo anterior.
+	Backup string `json:"backup,omitempty"`
+}
+
+// ParseConflictMode valida o valor de --on-conflict.
+func ParseConflictMode(value string) (ConflictMode, error) {
+	for _, mode := range ConflictModes {
+		if string(mode) == value {
+			return mode, nil
+		}
+	}
+	names := make([]string, len(ConflictModes))
+	for i, mode := range ConflictModes {
+		names[i] = string(mode)
+	}
+	return "", fmt.Errorf("invalid on-conflict mode %q (use %s)", value, strings.Join(names, ", "))
+}
+
+// conflictPolicy aplica o modo de conflito de uma renderização.
+type conflictPolicy struct {
+	mode    ConflictMode
+	resolve ConflictResolver
+	// backupDir, relativo à saída, substitui o sufixo OrigSuffix quando informado.
+	backupDir string
+}
+
+func newConflictPolicy(req RenderRequest) (*conflictPolicy, error) {
+	if req.OnConflict == "" {
+		return nil, nil
+	}
+	if req.Overwrite {
+		return nil, errors.New("overwrite and on-conflict are mutually exclusive")
+	}
+	mode, err := ParseConflictMode(string(req.OnConflict))
+	if err != nil {
+		return nil, err
+	}
+	policy := &conflictPolicy{mode: mode, resolve: req.ResolveConflict}
+	if mode == ConflictPrompt && policy.resolve == nil {
+		return nil, errors.New("on-conflict prompt requires a conflict resolver")
+	}
+	if req.BackupDir {
+		policy.backupDir = filepath.Join(ConflictBackupDir, time.Now().UTC().Format("20060102-150405"))
+	}
+	return policy, nil
+}
+
+// decide retorna a ação para um arquivo em conflito.
+func (p *conflictPolicy) decide(rel string) (ConflictMode, error) {
+	if p.mode != ConflictPrompt {
+		return p.mode, nil
+	}
+	action, err := p.resolve(rel)
+	if err != nil {
+		return "", err
+	}
+	switch action {
+	case ConflictSkip, ConflictOverwrite, ConflictBackup:
+		return action, nil
+	}
+	return "", fmt.Errorf("conflict resolver returned %q for %s", action, rel)
+}
+
+// mergeEntry é um caminho do staging e o que fazer com ele na saída.
+type mergeEntry struct {
+	rel   string
+	isDir bool
+	// exists indica um arquivo da saída que difere do staging.
+	exists bool
+	action ConflictMode
+}
+
+// merge promove o staging para uma saída existente arquivo a arquivo: arquivos
+// novos entram, idênticos são ignorados, conflitos seguem a política e os
+// arquivos da saída que não vêm do template ficam intactos, exceto os listados
+// em remove, que vão para o diretório de rollback. Todas as decisões
+// são tomadas antes da primeira alteração; se alguma etapa falhar, o estado
+// anterior é restaurado. Como promote, retorna o diretório de rollback, que o
+// chamador remove depois da promoção.
+func (s *stage) merge(policy *conflictPolicy) ([]ResolvedConflict, string, error) {
+	entries, err := s.mergeEntries()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var conflicts []string
+	for i := range entries {
+		entry := &entries[i]
+		if !entry.exists || entry.action != "" {
+			continue
+		}
+		if policy.mode == ConflictFail {
+			conflicts = append(conflicts, entry.rel)
+			continue
+		}
+		if entry.action, err = policy.decide(entry.rel); err != nil {
+			return nil, "", err
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, "", fmt.Errorf("%d file(s) differ from the template in %s: %s", len(conflicts), s.output, strings.Join(conflicts, ", "))
+	}
+
+	rollbackDir, err := os.MkdirTemp(filepath.Dir(s.output), fmt.Sprintf(backupPattern, filepath.Base(s.output)))
+	if err != nil {
+		return nil, "", fmt.Errorf("create backup dir: %w", err)
+	}
+
+	type move struct{ from, to string }
+	var (
+		created []string
+		moved   []move
+		placed  []string
+	)
+	rollback := func(cause error) error {
+		var errs []error
+		for i := len(placed) - 1; i >= 0; i-- {
+			errs = append(errs, os.Remove(placed[i]))
+		}
+		for i := len(moved) - 1; i >= 0; i-- {
+			errs = append(errs, os.Rename(moved[i].to, moved[i].from))
+		}
+		for i := len(created) - 1; i >= 0; i-- {
+			errs = append(errs, os.RemoveAll(created[i]))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return fmt.Errorf("promote output: %w (rollback failed, previous contents kept in %s: %v)", cause, rollbackDir, err)
+		}
+		_ = os.RemoveAll(rollbackDir)
+		return fmt.Errorf("promote output: %w", cause)
+	}
+	ensureDir := func(dir string) error {
+		top, err := missingAncestor(dir)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		if top != "" {
+			created = append(created, top)
+		}
+		return nil
+	}
+	relocate := func(from, to string) error {
+		if err := ensureDir(filepath.Dir(to)); err != nil {
+			return err
+		}
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+		moved = append(moved, move{from: from, to: to})
+		return nil
+	}
+
+	var resolved []ResolvedConflict
+	for _, entry := range entries {
+		target := filepath.Join(s.output, filepath.FromSlash(entry.rel))
+		if entry.isDir {
+			if err := ensureDir(target); err != nil {
+				return nil, "", rollback(err)
+			}
+			continue
+		}
+
+		switch {
+		case !entry.exists:
+		case entry.action == ConflictSkip:
+			resolved = append(resolved, ResolvedConflict{Path: entry.rel, Action: ConflictSkip})
+			continue
+		case entry.action == ConflictOverwrite:
+			if err := relocate(target, filepath.Join(rollbackDir, filepath.FromSlash(entry.rel))); err != nil {
+				return nil, "", rollback(err)
+			}
+			if entry.rel != LockfileName {
+				resolved = append(resolved, ResolvedConflict{Path: entry.rel, Action: ConflictOverwrite})
+			}
+		case entry.action == ConflictBackup:
+			backup, err := policy.backupPath(s.output, entry.rel)
+			if err != nil {
+				return nil, "", rollback(err)
+			}
+			if err := relocate(target, filepath.Join(s.output, filepath.FromSlash(backup))); err != nil {
+				return nil, "", rollback(err)
+			}
+			resolved = append(resolved, ResolvedConflict{Path: entry.rel, Action: ConflictBackup, Backup: backup})
+		}
+
+		if err := ensureDir(filepath.Dir(target)); err != nil {
+			return nil, "", rollback(err)
+		}
+		if err := os.Rename(filepath.Join(s.dir, filepath.FromSlash(entry.rel)), target); err != nil {
+			return nil, "", rollback(err)
+		}
+		placed = append(placed, target)
+	}
+	for _, rel := range s.remove {
+		target := filepath.Join(s.output, filepath.FromSlash(rel))
+		if err := relocate(target, filepath.Join(rollbackDir, filepath.FromSlash(rel))); err != nil {
+			return nil, "", rollback(err)
+		}
+	}
+	return resolved, rollbackDir, nil
+}
+
+// mergeEntries lista, em ordem lexical, os diretórios e arquivos do staging que
+// não existem na saída e os arquivos que diferem dela; idênticos são omitidos.
+// O lockfile é sempre substituído e os diretórios de vcsDirs nunca são mesclados.
+func (s *stage) mergeEntries() ([]mergeEntry, error) {
+	var entries []mergeEntry
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == s.dir {
+			return err
+		}
+		if d.IsDir() && vcsDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		entry := mergeEntry{rel: filepath.ToSlash(rel), isDir: d.IsDir()}
+		target := filepath.Join(s.output, rel)
+		info, err := os.Lstat(target)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			entries = append(entries, entry)
+			return nil
+		case err != nil:
+			return err
+		case entry.isDir:
+			if !info.IsDir() {
+				return fmt.Errorf("%s is a directory in the template but not in the output", entry.rel)
+			}
+			return nil
+		case entry.rel == LockfileName:
+			entry.action = ConflictOverwrite
+		default:
+			same, err := sameFile(path, target, info)
+			if err != nil || same {
+				return err
+			}
+		}
+		entry.exists = true
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("compare output: %w", err)
+	}
+	return entries, nil
+}
+
+// backupPath escolhe onde guardar o conteúdo anterior de rel: ao lado, com
+// OrigSuffix e, se preciso, um número, ou no diretório de backups.
+func (p *conflictPolicy) backupPath(output, rel string) (string, error) {
+	if p.backupDir != "" {
+		return filepath.ToSlash(filepath.Join(p.backupDir, filepath.FromSlash(rel))), nil
+	}
+	candidate := rel + OrigSuffix
+	for n := 1; ; n++ {
+		_, err := os.Lstat(filepath.Join(output, filepath.FromSlash(candidate)))
+		if errors.Is(err, fs.ErrNotExist) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s%s.%d", rel, OrigSuffix, n)
+	}
+}
+
+// sameFile compara o conteúdo de a com o arquivo regular b em blocos, sem
+// carregar arquivos grandes inteiros.
+func sameFile(a, b string, info fs.FileInfo) (bool, error) {
+	if !info.Mode().IsRegular() {
+		return false, nil
+	}
+	staged, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	if staged.Size() != info.Size() {
+		return false, nil
+	}
+
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	bufA, bufB := make([]byte, 32*1024), make([]byte, 32*1024)
+	for {
+		na, errA := io.ReadFull(fa, bufA)
+		nb, errB := io.ReadFull(fb, bufB)
+		if !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		if errA == io.EOF || errA == io.ErrUnexpectedEOF {
+			return errB == io.EOF || errB == io.ErrUnexpectedEOF, nil
+		}
+		if errA != nil {
+			return false, errA
+		}
+		if errB != nil {
+			return false, errB
+		}
+	}
+}
+
+// missingAncestor retorna o diretório mais alto de dir que ainda não existe,
+// ou vazio se dir já existe.
+func missingAncestor(dir string) (string, error) {
+	top := ""
+	for {
+		_, err := os.Lstat(dir)
+		if err == nil {
+			return top, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		top = dir
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return top, nil
+		}
+		dir = parent
+	}
+}
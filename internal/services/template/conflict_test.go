@@ -0,0 +1,307 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+// conflictFixture gera o template de teste em uma saída que já tem um arquivo
+// alterado localmente, um idêntico e um que não vem do template.
+func conflictFixture(t *testing.T) (*Service, string) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	templateDir := writeTemplateFiles(t, map[string]string{
+		"README.md":      "# template\n",
+		"main.go":        "package main\n",
+		"docs/guide.md":  "guide\n",
+		"config.yaml":    "name: template\n",
+		"nested/new.txt": "new\n",
+	})
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(&models.TemplateMetadata{Name: "demo"}, templateDir, nil).AnyTimes()
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, zerolog.Nop(), prometheus.NewRegistry(), mockRepo)
+
+	output := filepath.Join(t.TempDir(), "project")
+	for name, content := range map[string]string{
+		"README.md":   "# local\n",
+		"main.go":     "package main\n",
+		"config.yaml": "name: local\n",
+		"local.txt":   "mine\n",
+	} {
+		path := filepath.Join(output, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return service, output
+}
+
+func readOutput(t *testing.T, output, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(output, filepath.FromSlash(name)))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestServiceRenderOnConflict(t *testing.T) {
+	t.Parallel()
+
+	t.Run("skip", func(t *testing.T) {
+		t.Parallel()
+		service, output := conflictFixture(t)
+		resp, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictSkip})
+		require.NoError(t, err)
+		require.Equal(t, []ResolvedConflict{
+			{Path: "README.md", Action: ConflictSkip},
+			{Path: "config.yaml", Action: ConflictSkip},
+		}, resp.Conflicts)
+		require.Equal(t, "# local\n", readOutput(t, output, "README.md"))
+		require.Equal(t, "mine\n", readOutput(t, output, "local.txt"))
+		require.Equal(t, "new\n", readOutput(t, output, "nested/new.txt"))
+		require.Equal(t, "guide\n", readOutput(t, output, "docs/guide.md"))
+		require.FileExists(t, filepath.Join(output, LockfileName))
+
+		entries, err := os.ReadDir(filepath.Dir(output))
+		require.NoError(t, err)
+		require.Len(t, entries, 1, "staging e rollback removidos")
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		t.Parallel()
+		service, output := conflictFixture(t)
+		resp, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictOverwrite})
+		require.NoError(t, err)
+		require.Len(t, resp.Conflicts, 2)
+		require.Equal(t, "# template\n", readOutput(t, output, "README.md"))
+		require.Equal(t, "name: template\n", readOutput(t, output, "config.yaml"))
+		require.Equal(t, "mine\n", readOutput(t, output, "local.txt"), "arquivos fora do template são preservados")
+	})
+
+	t.Run("backup", func(t *testing.T) {
+		t.Parallel()
+		service, output := conflictFixture(t)
+		require.NoError(t, os.WriteFile(filepath.Join(output, "README.md.orig"), []byte("older\n"), 0o644))
+
+		resp, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictBackup})
+		require.NoError(t, err)
+		require.Equal(t, []ResolvedConflict{
+			{Path: "README.md", Action: ConflictBackup, Backup: "README.md.orig.1"},
+			{Path: "config.yaml", Action: ConflictBackup, Backup: "config.yaml.orig"},
+		}, resp.Conflicts)
+		require.Equal(t, "# template\n", readOutput(t, output, "README.md"))
+		require.Equal(t, "older\n", readOutput(t, output, "README.md.orig"))
+		require.Equal(t, "# local\n", readOutput(t, output, "README.md.orig.1"))
+		require.Equal(t, "name: local\n", readOutput(t, output, "config.yaml.orig"))
+	})
+
+	t.Run("backup dir", func(t *testing.T) {
+		t.Parallel()
+		service, output := conflictFixture(t)
+		resp, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictBackup, BackupDir: true})
+		require.NoError(t, err)
+		require.Len(t, resp.Conflicts, 2)
+		backup := resp.Conflicts[0].Backup
+		require.True(t, strings.HasPrefix(backup, ConflictBackupDir+"/"), backup)
+		require.True(t, strings.HasSuffix(backup, "/README.md"), backup)
+		require.Equal(t, "# local\n", readOutput(t, output, backup))
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		t.Parallel()
+		service, output := conflictFixture(t)
+		_, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictFail})
+		require.ErrorContains(t, err, "2 file(s) differ from the template")
+		require.ErrorContains(t, err, "README.md, config.yaml")
+		require.NoFileExists(t, filepath.Join(output, "nested", "new.txt"))
+		require.NoFileExists(t, filepath.Join(output, LockfileName))
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		t.Parallel()
+		service, output := conflictFixture(t)
+		var asked []string
+		resolve := func(path string) (ConflictMode, error) {
+			asked = append(asked, path)
+			if path == "README.md" {
+				return ConflictOverwrite, nil
+			}
+			return ConflictSkip, nil
+		}
+		resp, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictPrompt, ResolveConflict: resolve})
+		require.NoError(t, err)
+		require.Equal(t, []string{"README.md", "config.yaml"}, asked)
+		require.Len(t, resp.Conflicts, 2)
+		require.Equal(t, "# template\n", readOutput(t, output, "README.md"))
+		require.Equal(t, "name: local\n", readOutput(t, output, "config.yaml"))
+	})
+
+	t.Run("prompt aborted", func(t *testing.T) {
+		t.Parallel()
+		service, output := conflictFixture(t)
+		aborted := errors.New("aborted")
+		resolve := func(string) (ConflictMode, error) { return "", aborted }
+		_, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictPrompt, ResolveConflict: resolve})
+		require.ErrorIs(t, err, aborted)
+		require.Equal(t, "# local\n", readOutput(t, output, "README.md"))
+		require.NoFileExists(t, filepath.Join(output, LockfileName))
+	})
+}
+
+func TestServiceRenderOnConflictValidation(t *testing.T) {
+	t.Parallel()
+
+	service, output := conflictFixture(t)
+	_, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: "merge"})
+	require.ErrorContains(t, err, `invalid on-conflict mode "merge"`)
+
+	_, err = service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictSkip, Overwrite: true})
+	require.ErrorContains(t, err, "mutually exclusive")
+
+	_, err = service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictPrompt})
+	require.ErrorContains(t, err, "requires a conflict resolver")
+
+	plan, err := service.Plan(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output, OnConflict: ConflictBackup}, PlanOptions{})
+	require.NoError(t, err)
+	actions := map[string]PlanAction{}
+	for _, file := range plan.Files {
+		actions[file.Path] = file.Action
+	}
+	require.Equal(t, map[string]PlanAction{
+		"README.md":      PlanBackup,
+		"config.yaml":    PlanBackup,
+		"docs/guide.md":  PlanCreate,
+		"main.go":        PlanSkip,
+		"nested/new.txt": PlanCreate,
+	}, actions)
+}
+
+func TestServiceRenderKeepsOutputHistory(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	templateDir := writeTemplateFiles(t, map[string]string{"README.md": "# template\n"})
+	meta := &models.TemplateMetadata{
+		Name:  "demo",
+		Hooks: models.Hooks{PostRender: []models.HookStep{{Action: models.HookGitInit, Message: "chore: scaffold"}}},
+	}
+	git := func(t *testing.T, dir string, args ...string) string {
+		t.Helper()
+		args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@localhost"}, args...)
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+		return string(out)
+	}
+
+	for name, req := range map[string]RenderRequest{
+		"skip":      {OnConflict: ConflictSkip},
+		"overwrite": {OnConflict: ConflictOverwrite},
+		"backup":    {OnConflict: ConflictBackup},
+		"replace":   {Overwrite: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			service := hookService(t, meta, templateDir)
+			output := filepath.Join(t.TempDir(), "project")
+			_, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: output})
+			require.NoError(t, err)
+
+			require.NoError(t, os.WriteFile(filepath.Join(output, "README.md"), []byte("# local\n"), 0o644))
+			git(t, output, "commit", "--quiet", "-am", "docs: local readme")
+			head := git(t, output, "rev-parse", "HEAD")
+
+			req.TemplateName, req.OutputDir = "demo", output
+			_, err = service.Render(context.Background(), req)
+			require.NoError(t, err)
+			require.Equal(t, head, git(t, output, "rev-parse", "HEAD"))
+			require.Equal(t, "docs: local readme\nchore: scaffold\n", git(t, output, "log", "--format=%s"))
+		})
+	}
+}
+
+func TestStageMergeRollback(t *testing.T) {
+	t.Parallel()
+
+	parent := t.TempDir()
+	output := filepath.Join(parent, "out")
+	require.NoError(t, os.MkdirAll(output, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(output, "a.txt"), []byte("old a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(output, "b.txt"), []byte("old b"), 0o644))
+	// O diretório de backup aponta para um arquivo, então o backup de b.txt
+	// falha depois que a.txt e os arquivos novos já foram promovidos.
+	require.NoError(t, os.WriteFile(filepath.Join(output, "blocker"), []byte("file"), 0o644))
+
+	staging, err := newStage(output, true)
+	require.NoError(t, err)
+	for name, content := range map[string]string{"a.txt": "new a", "b.txt": "new b", "0new.txt": "new", "0dir/x.txt": "x"} {
+		path := filepath.Join(staging.dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	policy := &conflictPolicy{mode: ConflictPrompt, backupDir: "blocker", resolve: func(path string) (ConflictMode, error) {
+		if path == "a.txt" {
+			return ConflictOverwrite, nil
+		}
+		return ConflictBackup, nil
+	}}
+	_, _, err = staging.merge(policy)
+	require.ErrorContains(t, err, "promote output")
+	require.NoError(t, staging.discard())
+
+	require.Equal(t, "old a", readOutput(t, output, "a.txt"))
+	require.Equal(t, "old b", readOutput(t, output, "b.txt"))
+	require.NoFileExists(t, filepath.Join(output, "0new.txt"))
+	require.NoDirExists(t, filepath.Join(output, "0dir"))
+	entries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "rollback removido após restaurar a saída")
+}
+
+func TestStageMergeRemove(t *testing.T) {
+	t.Parallel()
+
+	output := filepath.Join(t.TempDir(), "out")
+	require.NoError(t, os.MkdirAll(output, 0o755))
+	for _, name := range []string{"a.txt", "gone.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(output, name), []byte("old"), 0o644))
+	}
+
+	staging, err := newStage(output, true)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(staging.dir, "a.txt"), []byte("new"), 0o644))
+	// missing.txt não existe: a remoção falha depois de gone.txt e a.txt.
+	staging.remove = []string{"gone.txt", "missing.txt"}
+
+	_, _, err = staging.merge(&conflictPolicy{mode: ConflictOverwrite})
+	require.ErrorContains(t, err, "promote output")
+	require.Equal(t, "old", readOutput(t, output, "a.txt"))
+	require.Equal(t, "old", readOutput(t, output, "gone.txt"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(staging.dir, "a.txt"), []byte("new"), 0o644))
+	staging.remove = []string{"gone.txt"}
+	_, backup, err := staging.merge(&conflictPolicy{mode: ConflictOverwrite})
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(backup))
+	require.NoError(t, staging.discard())
+	require.Equal(t, "new", readOutput(t, output, "a.txt"))
+	require.NoFileExists(t, filepath.Join(output, "gone.txt"))
+}
@@ -0,0 +1,47 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+)
+
+// moduleVariable é a variável que define o caminho do módulo Go gerado.
+const moduleVariable = "module_name"
+
+// moduleTransforms prepara a reescrita do módulo declarado no go.mod da raiz do
+// template para o valor de module_name, dispensando {{ }} em cada import. Com
+// herança, o módulo de cada camada é reescrito.
+func moduleTransforms(layers []string, values map[string]any) ([]string, []pkgtemplate.Transform, error) {
+	target := stringValue(values, moduleVariable)
+	if target == "" {
+		return nil, nil, nil
+	}
+
+	var sources []string
+	for i := len(layers) - 1; i >= 0; i-- {
+		data, err := os.ReadFile(filepath.Join(layers[i], "go.mod"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("read template go.mod: %w", err)
+		}
+
+		source := pkgtemplate.ModulePath(data)
+		if source == "" || source == target || strings.Contains(source, "{{") || containsString(sources, source) {
+			continue
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		return nil, nil, nil
+	}
+	// Uma única transformação reescreve cada caminho uma vez só, pelo módulo mais
+	// longo, mesmo quando o módulo de uma camada prefixa o destino.
+	return sources, []pkgtemplate.Transform{pkgtemplate.GoModulesTransform(sources, target)}, nil
+}
@@ -0,0 +1,352 @@
+package template
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+)
+
+// Fases de execução de hooks.
+const (
+	PhasePreRender  = "pre_render"
+	PhasePostRender = "post_render"
+)
+
+const (
+	defaultHookTimeout   = 2 * time.Minute
+	defaultCommitMessage = "Initial commit"
+	// hookOutputTail limita a saída anexada a um HookError.
+	hookOutputTail = 2048
+)
+
+// HookError indica que um passo obrigatório de hook falhou.
+type HookError struct {
+	Phase  string
+	Step   string
+	Output string
+	Err    error
+}
+
+func (e *HookError) Error() string {
+	msg := fmt.Sprintf("hook %s %q failed: %v", e.Phase, e.Step, e.Err)
+	if e.Output != "" {
+		msg += "\n" + e.Output
+	}
+	return msg
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// validateHooks verifica os passos declarados antes de qualquer arquivo ser gerado.
+func validateHooks(hooks models.Hooks, allowCommands bool) error {
+	var errs []error
+	phases := []struct {
+		name  string
+		steps []models.HookStep
+	}{{PhasePreRender, hooks.PreRender}, {PhasePostRender, hooks.PostRender}}
+	for _, p := range phases {
+		phase := p.name
+		for i, step := range p.steps {
+			name := hookName(step, i)
+			switch step.Action {
+			case models.HookGofmt, models.HookGoModTidy, models.HookGitInit:
+			case models.HookChmod:
+				if _, err := parseMode(step.Mode); err != nil {
+					errs = append(errs, fmt.Errorf("hook %s %q: %w", phase, name, err))
+				}
+				if len(step.Paths) == 0 {
+					errs = append(errs, fmt.Errorf("hook %s %q: paths is required", phase, name))
+				}
+				for _, pattern := range step.Paths {
+					if !pkgtemplate.ValidGlob(pattern) {
+						errs = append(errs, fmt.Errorf("hook %s %q: invalid path pattern %s", phase, name, pattern))
+					}
+				}
+			case models.HookCommand:
+				if len(step.Command) == 0 {
+					errs = append(errs, fmt.Errorf("hook %s %q: command is required", phase, name))
+				} else if !allowCommands {
+					errs = append(errs, fmt.Errorf("hook %s %q runs %q; command hooks require --allow-hooks", phase, name, step.Command[0]))
+				}
+			default:
+				errs = append(errs, fmt.Errorf("hook %s %q: unknown action %q", phase, name, step.Action))
+			}
+			if strings.TrimSpace(step.When) != "" {
+				if _, err := pkgtemplate.ParseCondition(step.When); err != nil {
+					errs = append(errs, fmt.Errorf("hook %s %q: %w", phase, name, err))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runHooks executa os passos de uma fase no diretório de staging. Passos
+// opcionais que falham geram apenas aviso; os demais interrompem com
+// *HookError. Segredos são ocultados na saída registrada e nos erros.
+func (s *Service) runHooks(ctx context.Context, phase string, steps []models.HookStep, staging *stage, values map[string]any, secrets *secrets) error {
+	for i, step := range steps {
+		name := hookName(step, i)
+		if strings.TrimSpace(step.When) != "" {
+			cond, err := pkgtemplate.ParseCondition(step.When)
+			if err != nil {
+				return &HookError{Phase: phase, Step: name, Err: err}
+			}
+			if !cond.Eval(values) {
+				continue
+			}
+		}
+
+		start := time.Now()
+		output, err := s.runHook(ctx, step, staging, values)
+		output = secrets.redact(output)
+		err = secrets.redactError(err)
+		s.logHookOutput(phase, name, output)
+		if err != nil {
+			if step.Optional {
+				s.logger.Warn().Err(err).Str("phase", phase).Str("hook", name).Msg("hook opcional falhou, continuando")
+				continue
+			}
+			return &HookError{Phase: phase, Step: name, Output: tail(output, hookOutputTail), Err: err}
+		}
+		s.logger.Info().Str("phase", phase).Str("hook", name).Dur("duration", time.Since(start)).Msg("hook executado")
+	}
+	return nil
+}
+
+func (s *Service) runHook(ctx context.Context, step models.HookStep, staging *stage, values map[string]any) (string, error) {
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	rel, err := hookDir(step.Dir, values)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(staging.dir, rel)
+	env, err := hookEnv(step.Env, values)
+	if err != nil {
+		return "", err
+	}
+
+	switch step.Action {
+	case models.HookGofmt:
+		return "", gofmtTree(ctx, dir)
+	case models.HookGoModTidy:
+		return execHook(ctx, dir, env, "go", "mod", "tidy")
+	case models.HookGitInit:
+		return gitInit(ctx, dir, filepath.Join(staging.output, rel), env, step.Message)
+	case models.HookChmod:
+		mode, err := parseMode(step.Mode)
+		if err != nil {
+			return "", err
+		}
+		return "", chmodTree(dir, step.Paths, mode)
+	case models.HookCommand:
+		args := make([]string, len(step.Command))
+		for i, arg := range step.Command {
+			if args[i], err = pkgtemplate.RenderString("command", arg, values); err != nil {
+				return "", fmt.Errorf("render command: %w", err)
+			}
+		}
+		return execHook(ctx, dir, env, args[0], args[1:]...)
+	default:
+		return "", fmt.Errorf("unknown action %q", step.Action)
+	}
+}
+
+func (s *Service) logHookOutput(phase, name, output string) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		s.logger.Info().Str("phase", phase).Str("hook", name).Msg(scanner.Text())
+	}
+}
+
+func hookName(step models.HookStep, index int) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return fmt.Sprintf("%s#%d", step.Action, index+1)
+}
+
+// namedSteps copia steps preenchendo Name com hookName, para que os nomes não
+// mudem quando a fase é executada em partes.
+func namedSteps(steps []models.HookStep) []models.HookStep {
+	named := make([]models.HookStep, len(steps))
+	for i, step := range steps {
+		step.Name = hookName(step, i)
+		named[i] = step
+	}
+	return named
+}
+
+// hookDir resolve o diretório de trabalho relativo à saída, que precisa ficar
+// dentro dela.
+func hookDir(dir string, values map[string]any) (string, error) {
+	if dir == "" {
+		return ".", nil
+	}
+	rendered, err := pkgtemplate.RenderString("dir", dir, values)
+	if err != nil {
+		return "", fmt.Errorf("render dir: %w", err)
+	}
+	clean := filepath.Clean(filepath.FromSlash(rendered))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("hook dir must be inside the output directory: %s", rendered)
+	}
+	return clean, nil
+}
+
+func hookEnv(env map[string]string, values map[string]any) ([]string, error) {
+	result := os.Environ()
+	for key, value := range env {
+		rendered, err := pkgtemplate.RenderString(key, value, values)
+		if err != nil {
+			return nil, fmt.Errorf("render env %s: %w", key, err)
+		}
+		result = append(result, key+"="+rendered)
+	}
+	return result, nil
+}
+
+func execHook(ctx context.Context, dir string, env []string, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out: %w", ctx.Err())
+	}
+	return out.String(), err
+}
+
+// gitInit cria o repositório em dir, no staging, e o commit inicial. Nada é
+// feito se target, o diretório correspondente na saída real, ou dir já têm um
+// repositório, já que o staging nunca substitui o .git da saída.
+func gitInit(ctx context.Context, dir, target string, env []string, message string) (string, error) {
+	for _, path := range []string{target, dir} {
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			return "repositório git já existe, nada a fazer\n", nil
+		}
+	}
+	if message == "" {
+		message = defaultCommitMessage
+	}
+
+	var output strings.Builder
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"add", "--all"},
+		{"-c", "user.name=mcp-templates", "-c", "user.email=mcp-templates@localhost", "commit", "--quiet", "-m", message},
+	}
+	if name, _ := execHook(ctx, dir, env, "git", "config", "user.name"); strings.TrimSpace(name) != "" {
+		steps[2] = []string{"commit", "--quiet", "-m", message}
+	}
+	for _, args := range steps {
+		out, err := execHook(ctx, dir, env, "git", args...)
+		output.WriteString(out)
+		if err != nil {
+			return output.String(), err
+		}
+	}
+	return output.String(), nil
+}
+
+// gofmtTree formata em processo todos os arquivos .go abaixo de dir.
+func gofmtTree(ctx context.Context, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == "vendor" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		formatted, err := format.Source(data)
+		if err != nil {
+			rel, _ := filepath.Rel(dir, path)
+			return fmt.Errorf("gofmt %s: %w", filepath.ToSlash(rel), err)
+		}
+		if bytes.Equal(data, formatted) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, formatted, info.Mode())
+	})
+}
+
+// chmodTree aplica mode aos arquivos que casam com algum dos padrões.
+func chmodTree(dir string, patterns []string, mode os.FileMode) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		slashRel := filepath.ToSlash(rel)
+		for _, pattern := range patterns {
+			if pkgtemplate.MatchGlob(pattern, slashRel) {
+				return os.Chmod(path, mode)
+			}
+		}
+		return nil
+	})
+}
+
+func parseMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return 0, errors.New("mode is required")
+	}
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || value > 0o7777 {
+		return 0, fmt.Errorf("invalid mode %q, use octal such as 0755", mode)
+	}
+	return os.FileMode(value), nil
+}
+
+func tail(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
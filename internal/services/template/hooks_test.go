@@ -0,0 +1,199 @@
+package template
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+func hookService(t *testing.T, meta *models.TemplateMetadata, templateDir string) *Service {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), meta.Name).Return(meta, templateDir, nil).AnyTimes()
+
+	cfg := config.RenderingConfig{OperationTimeout: 10 * time.Second, MaxRetryAttempts: 1}
+	return New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+}
+
+func TestServiceRenderRunsBuiltinHooks(t *testing.T) {
+	t.Parallel()
+
+	templateDir := writeTemplateFiles(t, map[string]string{
+		"main.go":         "package main\nfunc main()  {  }\n",
+		"scripts/run.sh":  "#!/bin/sh\n",
+		"scripts/lib.txt": "lib\n",
+	})
+	meta := &models.TemplateMetadata{
+		Name: "demo",
+		Hooks: models.Hooks{PostRender: []models.HookStep{
+			{Action: models.HookGofmt},
+			{Name: "scripts", Action: models.HookChmod, Paths: []string{"scripts/*.sh"}, Mode: "0755"},
+		}},
+	}
+
+	outputDir := t.TempDir()
+	_, err := hookService(t, meta, templateDir).Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: outputDir})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "main.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package main\n\nfunc main() {}\n", string(data))
+	lock, err := ReadLockfile(outputDir)
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	require.Equal(t, "sha256:"+hex.EncodeToString(sum[:]), lock.Files["main.go"], "hash calculado depois do gofmt")
+
+	info, err := os.Stat(filepath.Join(outputDir, "scripts", "run.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	info, err = os.Stat(filepath.Join(outputDir, "scripts", "lib.txt"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+}
+
+func TestServiceRenderGitInitHook(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	templateDir := writeTemplateFiles(t, map[string]string{
+		"README.md": "demo\n",
+		"main.go":   "package main\nfunc main()  {  }\n",
+	})
+	meta := &models.TemplateMetadata{
+		Name: "demo",
+		Hooks: models.Hooks{PostRender: []models.HookStep{
+			{Action: models.HookGofmt},
+			{Action: models.HookGitInit, Message: "chore: scaffold"},
+		}},
+	}
+
+	outputDir := t.TempDir()
+	_, err := hookService(t, meta, templateDir).Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: outputDir})
+	require.NoError(t, err)
+
+	out, err := exec.Command("git", "-C", outputDir, "log", "--format=%s", "-1").Output()
+	require.NoError(t, err)
+	require.Equal(t, "chore: scaffold\n", string(out))
+	out, err = exec.Command("git", "-C", outputDir, "status", "--porcelain").Output()
+	require.NoError(t, err)
+	require.Empty(t, string(out), "lockfile final incluído no commit inicial")
+}
+
+func TestGitInitKeepsOutputRepository(t *testing.T) {
+	t.Parallel()
+
+	staging, target := t.TempDir(), t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(target, ".git"), 0o755))
+
+	out, err := gitInit(context.Background(), staging, target, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, "repositório git já existe, nada a fazer\n", out)
+	require.NoDirExists(t, filepath.Join(staging, ".git"))
+}
+
+func TestServiceRenderCommandHookRequiresOptIn(t *testing.T) {
+	t.Parallel()
+
+	templateDir := writeTemplateFiles(t, map[string]string{"README.md": "demo\n"})
+	meta := &models.TemplateMetadata{
+		Name:     "demo",
+		Defaults: map[string]any{"name": "ultra"},
+		Hooks: models.Hooks{PostRender: []models.HookStep{{
+			Name:    "greet",
+			Action:  models.HookCommand,
+			Command: []string{"sh", "-c", "echo \"$GREETING\" > greeting.txt"},
+			Env:     map[string]string{"GREETING": "hello {{ .name }}"},
+			Dir:     ".",
+		}}},
+	}
+	service := hookService(t, meta, templateDir)
+
+	outputDir := t.TempDir()
+	_, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: outputDir})
+	require.ErrorContains(t, err, "command hooks require --allow-hooks")
+	require.NoFileExists(t, filepath.Join(outputDir, "README.md"))
+
+	_, err = service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: outputDir, AllowHooks: true})
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(outputDir, "greeting.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello ultra\n", string(data))
+}
+
+func TestServiceRenderRequiredHookFailure(t *testing.T) {
+	t.Parallel()
+
+	templateDir := writeTemplateFiles(t, map[string]string{"README.md": "demo\n"})
+	meta := &models.TemplateMetadata{
+		Name: "demo",
+		Hooks: models.Hooks{PostRender: []models.HookStep{
+			{Name: "optional", Action: models.HookCommand, Command: []string{"sh", "-c", "exit 3"}, Optional: true},
+			{Name: "broken", Action: models.HookCommand, Command: []string{"sh", "-c", "echo boom >&2; exit 1"}},
+		}},
+	}
+
+	_, err := hookService(t, meta, templateDir).Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    t.TempDir(),
+		AllowHooks:   true,
+	})
+	var hookErr *HookError
+	require.True(t, errors.As(err, &hookErr))
+	require.Equal(t, PhasePostRender, hookErr.Phase)
+	require.Equal(t, "broken", hookErr.Step)
+	require.Contains(t, hookErr.Output, "boom")
+}
+
+func TestServiceRenderHookTimeout(t *testing.T) {
+	t.Parallel()
+
+	templateDir := writeTemplateFiles(t, map[string]string{"README.md": "demo\n"})
+	meta := &models.TemplateMetadata{
+		Name: "demo",
+		Hooks: models.Hooks{PreRender: []models.HookStep{
+			{Name: "slow", Action: models.HookCommand, Command: []string{"sleep", "5"}, Timeout: 50 * time.Millisecond},
+		}},
+	}
+
+	_, err := hookService(t, meta, templateDir).Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    t.TempDir(),
+		AllowHooks:   true,
+	})
+	require.ErrorContains(t, err, "timed out")
+}
+
+func TestValidateHooks(t *testing.T) {
+	t.Parallel()
+
+	err := validateHooks(models.Hooks{
+		PreRender: []models.HookStep{{Action: "unknown"}},
+		PostRender: []models.HookStep{
+			{Action: models.HookChmod, Mode: "rwx"},
+			{Action: models.HookCommand},
+		},
+	}, true)
+	require.ErrorContains(t, err, `unknown action "unknown"`)
+	require.ErrorContains(t, err, "invalid mode")
+	require.ErrorContains(t, err, "paths is required")
+	require.ErrorContains(t, err, "command is required")
+}
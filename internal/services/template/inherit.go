@@ -0,0 +1,154 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// layeredTemplate é um template com a cadeia de herança já resolvida.
+type layeredTemplate struct {
+	meta *models.TemplateMetadata
+	// layers vai do template mais básico ao solicitado.
+	layers []string
+	// partials são os diretórios de partials de todas as camadas, na mesma ordem.
+	partials []string
+	// partialPaths são os caminhos relativos dos partials, omitidos da saída.
+	partialPaths []string
+}
+
+// bases retorna as camadas herdadas, sem o template solicitado.
+func (l *layeredTemplate) bases() []string {
+	return l.layers[:len(l.layers)-1]
+}
+
+// ignorePatterns lista, no formato .gitignore, os caminhos que nunca geram
+// saída: template.yaml e os diretórios de partials.
+func (l *layeredTemplate) ignorePatterns() []string {
+	patterns := []string{"/template.yaml"}
+	for _, path := range l.partialPaths {
+		patterns = append(patterns, "/"+path+"/")
+	}
+	return patterns
+}
+
+// inherit resolve extends recursivamente e combina metadados e camadas.
+func (s *Service) inherit(ctx context.Context, meta *models.TemplateMetadata, templatePath string) (*layeredTemplate, error) {
+	return s.inheritChain(ctx, meta, templatePath, []string{meta.Name})
+}
+
+func (s *Service) inheritChain(ctx context.Context, meta *models.TemplateMetadata, templatePath string, chain []string) (*layeredTemplate, error) {
+	own := &layeredTemplate{meta: meta, layers: []string{templatePath}}
+	for _, dir := range meta.Partials {
+		clean := filepath.Clean(filepath.FromSlash(dir))
+		if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("partials dir must be inside the template: %s", dir)
+		}
+		path := filepath.Join(templatePath, clean)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("partials dir not found in template %s: %s", meta.Name, dir)
+		}
+		own.partials = append(own.partials, path)
+		own.partialPaths = append(own.partialPaths, filepath.ToSlash(clean))
+	}
+
+	if meta.Extends == "" {
+		return own, nil
+	}
+	for _, name := range chain {
+		if name == meta.Extends {
+			return nil, fmt.Errorf("template inheritance cycle: %s -> %s", strings.Join(chain, " -> "), meta.Extends)
+		}
+	}
+
+	baseMeta, basePath, err := s.loadTemplate(ctx, meta.Extends)
+	if err != nil {
+		return nil, fmt.Errorf("load base template %s: %w", meta.Extends, err)
+	}
+	base, err := s.inheritChain(ctx, baseMeta, basePath, append(chain, meta.Extends))
+	if err != nil {
+		return nil, err
+	}
+
+	return &layeredTemplate{
+		meta:         mergeMetadata(base.meta, meta),
+		layers:       append(base.layers, own.layers...),
+		partials:     append(base.partials, own.partials...),
+		partialPaths: append(base.partialPaths, own.partialPaths...),
+	}, nil
+}
+
+// mergeMetadata aplica os metadados do template derivado sobre os da base:
+// variáveis e variáveis computadas de mesma chave são substituídas, defaults e
+// perfis de mesmo nome são mesclados em profundidade, regras e delimitadores da
+// base vêm antes (e portanto perdem para os do derivado), hooks da base executam
+// primeiro, tags, copy_only e render_only são unidos e runtime_funcs vale se
+// qualquer camada o habilitar.
+func mergeMetadata(base, derived *models.TemplateMetadata) *models.TemplateMetadata {
+	merged := *derived
+	merged.Extends = ""
+	merged.Partials = nil
+
+	merged.Variables = append([]models.TemplateVariable(nil), base.Variables...)
+	for _, variable := range derived.Variables {
+		replaced := false
+		for i := range merged.Variables {
+			if merged.Variables[i].Key == variable.Key {
+				merged.Variables[i] = variable
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Variables = append(merged.Variables, variable)
+		}
+	}
+
+	merged.Defaults = valuespkg.Merge(valuespkg.Merge(nil, base.Defaults), derived.Defaults)
+	if len(base.Profiles)+len(derived.Profiles) > 0 {
+		merged.Profiles = make(map[string]map[string]any, len(base.Profiles)+len(derived.Profiles))
+		for _, profiles := range []map[string]map[string]any{base.Profiles, derived.Profiles} {
+			for name, values := range profiles {
+				merged.Profiles[name] = valuespkg.Merge(merged.Profiles[name], values)
+			}
+		}
+	}
+
+	if len(base.Computed)+len(derived.Computed) > 0 {
+		merged.Computed = make(map[string]string, len(base.Computed)+len(derived.Computed))
+		for _, computed := range []map[string]string{base.Computed, derived.Computed} {
+			for key, expr := range computed {
+				merged.Computed[key] = expr
+			}
+		}
+	}
+
+	merged.Rules = append(append([]models.FileRule(nil), base.Rules...), derived.Rules...)
+	merged.Hooks = models.Hooks{
+		PreRender:  append(append([]models.HookStep(nil), base.Hooks.PreRender...), derived.Hooks.PreRender...),
+		PostRender: append(append([]models.HookStep(nil), base.Hooks.PostRender...), derived.Hooks.PostRender...),
+	}
+
+	merged.Delimiters = append(append([]models.Delimiters(nil), base.Delimiters...), derived.Delimiters...)
+
+	merged.RuntimeFuncs = base.RuntimeFuncs || derived.RuntimeFuncs
+	merged.Tags = union(base.Tags, derived.Tags)
+	merged.CopyOnly = union(base.CopyOnly, derived.CopyOnly)
+	merged.RenderOnly = union(base.RenderOnly, derived.RenderOnly)
+	return &merged
+}
+
+func union(base, derived []string) []string {
+	result := append([]string(nil), base...)
+	for _, item := range derived {
+		if !containsString(result, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+func TestMergeMetadata(t *testing.T) {
+	t.Parallel()
+
+	base := &models.TemplateMetadata{
+		Name:      "base",
+		Variables: []models.TemplateVariable{{Key: "a"}, {Key: "b", Required: true}},
+		Defaults:  map[string]any{"a": "1", "b": "2"},
+		Rules:     []models.FileRule{{Exclude: "docs"}},
+		Tags:      []string{"go"},
+		CopyOnly:  []string{"deploy/**"},
+		Profiles:  map[string]map[string]any{"prod": {"a": "p", "db": map[string]any{"host": "db"}}, "dev": {"a": "d"}},
+		Computed:  map[string]string{"x": "{{ .a }}", "y": "{{ .b }}"},
+	}
+	derived := &models.TemplateMetadata{
+		Name:       "derived",
+		Extends:    "base",
+		Variables:  []models.TemplateVariable{{Key: "b"}, {Key: "c"}},
+		Defaults:   map[string]any{"b": "3"},
+		Rules:      []models.FileRule{{Include: "docs/README.md"}},
+		Tags:       []string{"go", "grpc"},
+		CopyOnly:   []string{"deploy/**", "grafana/**"},
+		Delimiters: []models.Delimiters{{Left: "[[", Right: "]]"}},
+		Profiles:   map[string]map[string]any{"prod": {"db": map[string]any{"port": 5432}}},
+		Computed:   map[string]string{"y": "{{ .c }}"},
+	}
+
+	merged := mergeMetadata(base, derived)
+	require.Equal(t, "derived", merged.Name)
+	require.Empty(t, merged.Extends)
+	require.Equal(t, []models.TemplateVariable{{Key: "a"}, {Key: "b"}, {Key: "c"}}, merged.Variables)
+	require.Equal(t, map[string]any{"a": "1", "b": "3"}, merged.Defaults)
+	require.Equal(t, []models.FileRule{{Exclude: "docs"}, {Include: "docs/README.md"}}, merged.Rules)
+	require.Equal(t, []string{"go", "grpc"}, merged.Tags)
+	require.Equal(t, []string{"deploy/**", "grafana/**"}, merged.CopyOnly)
+	require.Equal(t, []models.Delimiters{{Left: "[[", Right: "]]"}}, merged.Delimiters)
+	require.Equal(t, map[string]map[string]any{
+		"prod": {"a": "p", "db": map[string]any{"host": "db", "port": 5432}},
+		"dev":  {"a": "d"},
+	}, merged.Profiles)
+	require.Equal(t, map[string]string{"x": "{{ .a }}", "y": "{{ .c }}"}, merged.Computed)
+}
+
+func TestServiceRenderWithExtendsAndPartials(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	baseDir := writeTemplateFiles(t, map[string]string{
+		"Makefile":           "build:\n",
+		"Dockerfile":         "FROM base\n",
+		"shared/header.tmpl": "# {{ .name }}\n",
+		"README.md.tmpl":     "{{ template \"header\" . }}base\n",
+		"template.yaml":      "name: base\n",
+	})
+	derivedDir := writeTemplateFiles(t, map[string]string{
+		"Dockerfile":    "FROM derived\n",
+		"template.yaml": "extends: base\n",
+	})
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "derived").
+		Return(&models.TemplateMetadata{Name: "derived", Extends: "base"}, derivedDir, nil).Times(1)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "base").
+		Return(&models.TemplateMetadata{Name: "base", Partials: []string{"shared"}, Defaults: map[string]any{"name": "svc"}}, baseDir, nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	outputDir := t.TempDir()
+	_, err := service.Render(context.Background(), RenderRequest{TemplateName: "derived", OutputDir: outputDir})
+	require.NoError(t, err)
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(outputDir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+	require.Equal(t, "FROM derived\n", read("Dockerfile"))
+	require.Equal(t, "build:\n", read("Makefile"))
+	require.Equal(t, "# svc\nbase\n", read("README.md"))
+	require.NoDirExists(t, filepath.Join(outputDir, "shared"))
+	require.NoFileExists(t, filepath.Join(outputDir, "template.yaml"))
+}
+
+func TestServiceRenderExtendsCycle(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "a").
+		Return(&models.TemplateMetadata{Name: "a", Extends: "b"}, t.TempDir(), nil).Times(1)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "b").
+		Return(&models.TemplateMetadata{Name: "b", Extends: "a"}, t.TempDir(), nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	_, err := service.Render(context.Background(), RenderRequest{TemplateName: "a", OutputDir: t.TempDir()})
+	require.ErrorContains(t, err, "template inheritance cycle: a -> b -> a")
+}
+
+func TestServiceTemplateAppliesInheritance(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "derived").
+		Return(&models.TemplateMetadata{Name: "derived", Extends: "base", Variables: []models.TemplateVariable{{Key: "b"}}}, t.TempDir(), nil)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "base").
+		Return(&models.TemplateMetadata{Name: "base", Variables: []models.TemplateVariable{{Key: "a", Required: true}}, Defaults: map[string]any{"a": "1"}}, t.TempDir(), nil)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	meta, err := service.Template(context.Background(), "derived")
+	require.NoError(t, err)
+	require.Equal(t, []models.TemplateVariable{{Key: "a", Required: true}, {Key: "b"}}, meta.Variables)
+	require.Equal(t, map[string]any{"a": "1"}, meta.Defaults)
+
+	_, err = service.Template(context.Background(), "")
+	require.Error(t, err)
+}
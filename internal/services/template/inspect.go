@@ -0,0 +1,229 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// InspectReport descreve um template em detalhe: metadados, variáveis, hooks,
+// requisitos e a árvore que os valores padrão geram.
+type InspectReport struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"display_name"`
+	Description string   `json:"description"`
+	Version     string   `json:"version"`
+	Tags        []string `json:"tags"`
+	// Extends é o template base declarado, se houver.
+	Extends      string              `json:"extends,omitempty"`
+	Profile      string              `json:"profile,omitempty"`
+	Profiles     []string            `json:"profiles,omitempty"`
+	RuntimeFuncs bool                `json:"runtime_funcs,omitempty"`
+	Variables    []InspectedVariable `json:"variables"`
+	Computed     []InspectedComputed `json:"computed,omitempty"`
+	Hooks        models.Hooks        `json:"hooks"`
+	Requirements []Requirement       `json:"requirements,omitempty"`
+	// AllowHooks indica hooks command, que exigem autorização explícita.
+	AllowHooks bool `json:"allow_hooks,omitempty"`
+	// Files é a árvore gerada com os defaults; vazia, com FilesError, quando
+	// eles não bastam para renderizar.
+	Files      []InspectedFile `json:"files"`
+	FilesError string          `json:"files_error,omitempty"`
+	Summary    FileSummary     `json:"summary"`
+}
+
+// InspectedVariable é uma variável declarada com o default efetivo. Defaults
+// de variáveis secretas são mascarados.
+type InspectedVariable struct {
+	Key         string              `json:"key"`
+	Type        models.VariableType `json:"type"`
+	Required    bool                `json:"required"`
+	Secret      bool                `json:"secret,omitempty"`
+	Default     any                 `json:"default,omitempty"`
+	Choices     []string            `json:"choices,omitempty"`
+	Description string              `json:"description,omitempty"`
+}
+
+// InspectedComputed é uma variável computada e o valor que ela assume com os
+// defaults.
+type InspectedComputed struct {
+	Key        string `json:"key"`
+	Expression string `json:"expression"`
+	Value      any    `json:"value,omitempty"`
+}
+
+// Requirement é uma ferramenta externa exigida por um hook.
+type Requirement struct {
+	Tool     string `json:"tool"`
+	Hook     string `json:"hook"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// InspectedFile é um arquivo da árvore gerada.
+type InspectedFile struct {
+	Path string               `json:"path"`
+	Kind pkgtemplate.FileKind `json:"kind"`
+	Size int64                `json:"size"`
+}
+
+// FileSummary conta os arquivos gerados por tipo.
+type FileSummary struct {
+	Files     int   `json:"files"`
+	Dirs      int   `json:"dirs"`
+	Templates int   `json:"templates"`
+	Copied    int   `json:"copied"`
+	Binary    int   `json:"binary"`
+	TotalSize int64 `json:"total_size"`
+}
+
+// Inspect descreve o template com a herança aplicada. A árvore é avaliada como
+// em Plan, com os defaults e o perfil informado; se eles não bastarem, por
+// exemplo com variáveis obrigatórias sem default, o erro fica em FilesError e
+// o restante do relatório é mantido.
+func (s *Service) Inspect(ctx context.Context, name, profile string) (*InspectReport, error) {
+	if name == "" {
+		return nil, errors.New("template name is required")
+	}
+
+	meta, templatePath, err := s.loadTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	raw := meta
+	layered, err := s.inherit(ctx, raw, templatePath)
+	if err != nil {
+		return nil, err
+	}
+	meta = layered.meta
+	layers, err := templateLayers(meta, profile)
+	if err != nil {
+		return nil, err
+	}
+	defaults, _ := valuespkg.Resolve(layers)
+
+	report := &InspectReport{
+		Name:         meta.Name,
+		DisplayName:  meta.DisplayName,
+		Description:  meta.Description,
+		Version:      meta.Version,
+		Tags:         meta.Tags,
+		Extends:      raw.Extends,
+		Profile:      profile,
+		RuntimeFuncs: meta.RuntimeFuncs,
+		Hooks:        meta.Hooks,
+		Files:        []InspectedFile{},
+	}
+	for key := range meta.Profiles {
+		report.Profiles = append(report.Profiles, key)
+	}
+	sort.Strings(report.Profiles)
+
+	for _, variable := range meta.Variables {
+		if _, computed := meta.Computed[variable.Key]; computed {
+			continue
+		}
+		entry := InspectedVariable{
+			Key:         variable.Key,
+			Type:        variable.Type,
+			Required:    variable.Required,
+			Secret:      variable.Secret,
+			Choices:     variable.Choices,
+			Description: variable.Description,
+		}
+		if entry.Type == "" {
+			entry.Type = models.VariableTypeString
+		}
+		if value, ok := valuespkg.Lookup(defaults, variable.Key); ok && valuespkg.String(value) != "" {
+			entry.Default = value
+			if variable.Secret && !IsSecretReference(valuespkg.String(value)) {
+				entry.Default = MaskedValue
+			}
+		}
+		report.Variables = append(report.Variables, entry)
+	}
+
+	report.Requirements, report.AllowHooks = hookRequirements(meta.Hooks)
+
+	job, err := s.resolve(ctx, raw, templatePath, nil, profile, nil)
+	var values map[string]any
+	if err == nil {
+		values = job.secrets.mask(job.values, true)
+		err = s.inspectTree(ctx, job, report)
+	}
+	if err != nil {
+		report.FilesError = err.Error()
+	}
+
+	for key, expression := range meta.Computed {
+		entry := InspectedComputed{Key: key, Expression: expression}
+		if value, ok := valuespkg.Lookup(values, key); ok {
+			entry.Value = value
+		}
+		report.Computed = append(report.Computed, entry)
+	}
+	sort.Slice(report.Computed, func(i, j int) bool { return report.Computed[i].Key < report.Computed[j].Key })
+	return report, nil
+}
+
+func (s *Service) inspectTree(ctx context.Context, job *renderJob, report *InspectReport) error {
+	job.opts.Runtime = runtimeFor(job.meta, fillRuntime(time.Time{}, 0))
+	tree, err := pkgtemplate.BuildTree(ctx, job.templatePath, job.values, job.opts)
+	if err != nil {
+		return job.secrets.redactError(err)
+	}
+
+	report.Summary.Dirs = len(tree.Dirs)
+	for _, file := range tree.Files {
+		report.Files = append(report.Files, InspectedFile{Path: file.Path, Kind: file.Kind, Size: file.Size})
+		report.Summary.Files++
+		report.Summary.TotalSize += file.Size
+		switch file.Kind {
+		case pkgtemplate.FileRendered:
+			report.Summary.Templates++
+		case pkgtemplate.FileCopied:
+			report.Summary.Copied++
+		case pkgtemplate.FileBinary:
+			report.Summary.Binary++
+		}
+	}
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].Path < report.Files[j].Path })
+	return nil
+}
+
+// hookRequirements lista as ferramentas externas usadas pelos hooks e se algum
+// deles exige --allow-hooks. gofmt e chmod rodam no próprio processo.
+func hookRequirements(hooks models.Hooks) ([]Requirement, bool) {
+	var (
+		requirements []Requirement
+		allowHooks   bool
+	)
+	for _, step := range append(append([]models.HookStep(nil), hooks.PreRender...), hooks.PostRender...) {
+		tool := ""
+		switch step.Action {
+		case models.HookGoModTidy:
+			tool = "go"
+		case models.HookGitInit:
+			tool = "git"
+		case models.HookCommand:
+			allowHooks = true
+			if len(step.Command) > 0 && !strings.Contains(step.Command[0], "{{") {
+				tool = step.Command[0]
+			}
+		}
+		if tool == "" {
+			continue
+		}
+		hook := step.Name
+		if hook == "" {
+			hook = string(step.Action)
+		}
+		requirements = append(requirements, Requirement{Tool: tool, Hook: hook, Optional: step.Optional})
+	}
+	return requirements, allowHooks
+}
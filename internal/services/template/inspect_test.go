@@ -0,0 +1,111 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+)
+
+func TestServiceInspect(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	templateDir := writeTemplateFiles(t, map[string]string{
+		"README.md.tmpl":       "# {{ .service }}\n",
+		"deploy/chart.yaml":    "name: {{ .Values.name }}\n",
+		"cmd/{{ .slug }}.go":   "package main\n",
+		"docs/internal.md":     "internal\n",
+		"_partials/header.txt": "header\n",
+	})
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "static.bin"), []byte{0x00, 0x01, 0x02}, 0o644))
+
+	meta := &models.TemplateMetadata{
+		Name:        "demo",
+		DisplayName: "Demo",
+		Version:     "1.2.0",
+		Tags:        []string{"go"},
+		Variables: []models.TemplateVariable{
+			{Key: "service", Required: true, Description: "Nome do serviço"},
+			{Key: "tier", Type: models.VariableTypeEnum, Choices: []string{"free", "pro"}},
+			{Key: "api_token", Secret: true},
+			{Key: "slug"},
+		},
+		Defaults: map[string]any{"service": "Payments API", "tier": "free", "api_token": "s3cr3t-value"},
+		Profiles: map[string]map[string]any{"prod": {"tier": "pro"}},
+		Computed: map[string]string{"slug": "{{ kebab .service }}"},
+		Partials: []string{"_partials"},
+		Rules:    []models.FileRule{{Exclude: "docs"}},
+		CopyOnly: []string{"deploy/**"},
+		Hooks: models.Hooks{PostRender: []models.HookStep{
+			{Name: "tidy", Action: models.HookGoModTidy, Optional: true},
+			{Action: models.HookCommand, Command: []string{"make", "generate"}},
+			{Action: models.HookGofmt},
+		}},
+	}
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(meta, templateDir, nil).AnyTimes()
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, zerolog.Nop(), prometheus.NewRegistry(), mockRepo)
+
+	report, err := service.Inspect(context.Background(), "demo", "prod")
+	require.NoError(t, err)
+	require.Equal(t, "Demo", report.DisplayName)
+	require.Equal(t, []string{"prod"}, report.Profiles)
+
+	require.Equal(t, []InspectedVariable{
+		{Key: "service", Type: models.VariableTypeString, Required: true, Default: "Payments API", Description: "Nome do serviço"},
+		{Key: "tier", Type: models.VariableTypeEnum, Default: "pro", Choices: []string{"free", "pro"}},
+		{Key: "api_token", Type: models.VariableTypeString, Secret: true, Default: MaskedValue},
+	}, report.Variables)
+	require.Equal(t, []InspectedComputed{{Key: "slug", Expression: "{{ kebab .service }}", Value: "payments-api"}}, report.Computed)
+
+	require.Equal(t, []Requirement{{Tool: "go", Hook: "tidy", Optional: true}, {Tool: "make", Hook: "command"}}, report.Requirements)
+	require.True(t, report.AllowHooks)
+
+	require.Empty(t, report.FilesError)
+	require.Equal(t, []InspectedFile{
+		{Path: "README.md", Kind: pkgtemplate.FileRendered, Size: int64(len("# Payments API\n"))},
+		{Path: "cmd/payments-api.go", Kind: pkgtemplate.FileRendered, Size: int64(len("package main\n"))},
+		{Path: "deploy/chart.yaml", Kind: pkgtemplate.FileCopied, Size: int64(len("name: {{ .Values.name }}\n"))},
+		{Path: "static.bin", Kind: pkgtemplate.FileBinary, Size: 3},
+	}, report.Files)
+	require.Equal(t, FileSummary{Files: 4, Dirs: 2, Templates: 2, Copied: 1, Binary: 1, TotalSize: 15 + 13 + 25 + 3}, report.Summary)
+}
+
+func TestServiceInspectWithoutDefaults(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	templateDir := writeTemplateFiles(t, map[string]string{"README.md.tmpl": "# {{ .service }}\n"})
+	meta := &models.TemplateMetadata{
+		Name:      "demo",
+		Variables: []models.TemplateVariable{{Key: "service", Required: true}},
+	}
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(meta, templateDir, nil).AnyTimes()
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, zerolog.Nop(), prometheus.NewRegistry(), mockRepo)
+
+	report, err := service.Inspect(context.Background(), "demo", "")
+	require.NoError(t, err)
+	require.Len(t, report.Variables, 1)
+	require.Contains(t, report.FilesError, "service")
+	require.Empty(t, report.Files)
+
+	_, err = service.Inspect(context.Background(), "demo", "prod")
+	require.ErrorContains(t, err, `profile "prod" not defined`)
+}
@@ -0,0 +1,237 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// LintSeverity classifica um problema encontrado pelo lint.
+type LintSeverity string
+
+// Severidades do lint. Erros impedem a renderização; avisos indicam código morto.
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// Códigos dos problemas reportados pelo lint.
+const (
+	LintSyntax             = "syntax"
+	LintUndeclaredVariable = "undeclared-variable"
+	LintUnusedVariable     = "unused-variable"
+	LintUnknownFunction    = "unknown-function"
+	LintCollision          = "collision"
+	LintInvalidComputed    = "invalid-computed"
+)
+
+// LintIssue é um problema localizado em um arquivo do template.
+type LintIssue struct {
+	// Path é relativo à raiz do template; vazio para problemas do template.yaml.
+	Path     string       `json:"path,omitempty"`
+	Line     int          `json:"line,omitempty"`
+	Severity LintSeverity `json:"severity"`
+	Code     string       `json:"code"`
+	Message  string       `json:"message"`
+}
+
+// LintReport reúne os problemas de um template.
+type LintReport struct {
+	Template string      `json:"template"`
+	Issues   []LintIssue `json:"issues"`
+}
+
+// Count retorna quantos problemas têm a severidade informada.
+func (r *LintReport) Count(severity LintSeverity) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Lint analisa estaticamente todos os arquivos do template, incluindo camadas
+// herdadas e partials, sem depender de valores: erros de sintaxe, variáveis
+// usadas e não declaradas, variáveis declaradas e nunca usadas, funções
+// desconhecidas, variáveis computadas inválidas ou cíclicas e arquivos que
+// colidem após remover o sufixo ".tmpl".
+func (s *Service) Lint(ctx context.Context, name string) (*LintReport, error) {
+	if name == "" {
+		return nil, errors.New("template name is required")
+	}
+
+	meta, templatePath, err := s.loadTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	layered, err := s.inherit(ctx, meta, templatePath)
+	if err != nil {
+		return nil, err
+	}
+	meta = layered.meta
+
+	delimiters, err := delimiterRules(meta)
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := pkgtemplate.AnalyzeTree(templatePath, pkgtemplate.RenderOptions{
+		Ignore:     layered.ignorePatterns(),
+		Bases:      layered.bases(),
+		Partials:   layered.partials,
+		Delimiters: delimiters,
+		CopyOnly:   meta.CopyOnly,
+		RenderOnly: meta.RenderOnly,
+		Runtime:    runtimeFor(meta, models.RuntimeState{}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LintReport{Template: meta.Name}
+	declared := declaredVariables(meta)
+	used := make(map[string]bool)
+	add := func(issue LintIssue) { report.Issues = append(report.Issues, issue) }
+
+	for _, file := range analysis.Files {
+		if file.Err != nil {
+			add(LintIssue{Path: file.Path, Line: file.Err.Line, Severity: LintError, Code: LintSyntax, Message: file.Err.Message})
+			continue
+		}
+		for _, ref := range file.Variables {
+			used[ref.Name] = true
+			if !isDeclared(declared, ref.Name) {
+				add(LintIssue{Path: file.Path, Line: ref.Line, Severity: LintError, Code: LintUndeclaredVariable,
+					Message: fmt.Sprintf("variável %q não declarada em template.yaml", ref.Name)})
+			}
+		}
+		for _, ref := range file.UnknownFuncs {
+			add(LintIssue{Path: file.Path, Line: ref.Line, Severity: LintError, Code: LintUnknownFunction,
+				Message: fmt.Sprintf("função %q não definida", ref.Name)})
+		}
+	}
+
+	for _, collision := range analysis.Collisions {
+		add(LintIssue{Path: collision.Sources[0], Severity: LintError, Code: LintCollision,
+			Message: fmt.Sprintf("%s geram o mesmo arquivo %s", strings.Join(collision.Sources, " e "), collision.Target)})
+	}
+
+	if _, err := computedOrder(meta.Computed); err != nil {
+		add(LintIssue{Severity: LintError, Code: LintInvalidComputed,
+			Message: fmt.Sprintf("variáveis computadas inválidas: %v", err)})
+	}
+
+	for _, key := range metadataReferences(meta) {
+		used[key] = true
+	}
+	if used[moduleVariable] || hasGoMod(layered.layers) {
+		used[moduleVariable] = true
+	}
+
+	unused := make([]string, 0)
+	for key := range declared {
+		if !isDeclared(used, key) {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+	for _, key := range unused {
+		add(LintIssue{Severity: LintWarning, Code: LintUnusedVariable,
+			Message: fmt.Sprintf("variável %q declarada e nunca usada", key)})
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+	return report, nil
+}
+
+// declaredVariables reúne as chaves de variables, defaults e computed.
+func declaredVariables(meta *models.TemplateMetadata) map[string]bool {
+	declared := make(map[string]bool, len(meta.Variables)+len(meta.Defaults))
+	for _, variable := range meta.Variables {
+		declared[variable.Key] = true
+	}
+	for key := range meta.Defaults {
+		declared[key] = true
+	}
+	for key := range meta.Computed {
+		declared[key] = true
+	}
+	return declared
+}
+
+// isDeclared informa se path, ou um caminho que o contém ou que ele contém, está
+// em keys: declarar "database" cobre ".database.host" e declarar "database.host"
+// cobre o uso de ".database" inteiro.
+func isDeclared(keys map[string]bool, path string) bool {
+	if keys[path] {
+		return true
+	}
+	for key := range keys {
+		if strings.HasPrefix(path, key+valuespkg.Separator) || strings.HasPrefix(key, path+valuespkg.Separator) {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataReferences lista as variáveis usadas pelo próprio template.yaml:
+// variáveis computadas, condições de regras e hooks e os campos de hooks que
+// aceitam template.
+func metadataReferences(meta *models.TemplateMetadata) []string {
+	var keys []string
+	condition := func(expr string) {
+		if strings.TrimSpace(expr) == "" {
+			return
+		}
+		if cond, err := pkgtemplate.ParseCondition(expr); err == nil {
+			keys = append(keys, cond.Identifiers()...)
+		}
+	}
+	text := func(value string) {
+		for _, ref := range pkgtemplate.AnalyzeTemplate("template.yaml", value).Variables {
+			keys = append(keys, ref.Name)
+		}
+	}
+
+	for _, rule := range meta.Rules {
+		condition(rule.When)
+	}
+	for _, expr := range meta.Computed {
+		text(expr)
+	}
+	for _, step := range append(append([]models.HookStep(nil), meta.Hooks.PreRender...), meta.Hooks.PostRender...) {
+		condition(step.When)
+		text(step.Dir)
+		for _, arg := range step.Command {
+			text(arg)
+		}
+		for _, value := range step.Env {
+			text(value)
+		}
+	}
+	return keys
+}
+
+func hasGoMod(layers []string) bool {
+	for _, layer := range layers {
+		if _, err := os.Stat(filepath.Join(layer, "go.mod")); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package template
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+func TestServiceLint(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := writeTemplateFiles(t, map[string]string{
+		"template.yaml":  "name: demo\n",
+		"README.md.tmpl": "# {{ .name }}\n{{ .owner | shout }}\n",
+		"README.md":      "static\n",
+		"broken.txt":     "ok\n{{ if .name }}\n",
+		"docs/guide.md":  "{{ .docs_title }}\n",
+	})
+	meta := &models.TemplateMetadata{
+		Name:      "demo",
+		Variables: []models.TemplateVariable{{Key: "name"}, {Key: "unused"}, {Key: "with_docs"}},
+		Defaults:  map[string]any{"docs_title": "Guia"},
+		Rules:     []models.FileRule{{Exclude: "docs", When: "!with_docs"}},
+	}
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(meta, dir, nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	report, err := service.Lint(context.Background(), "demo")
+	require.NoError(t, err)
+
+	codes := map[string][]string{}
+	for _, issue := range report.Issues {
+		codes[issue.Code] = append(codes[issue.Code], issue.Path)
+	}
+	require.Equal(t, map[string][]string{
+		LintCollision:          {"README.md"},
+		LintUndeclaredVariable: {"README.md.tmpl"},
+		LintUnknownFunction:    {"README.md.tmpl"},
+		LintSyntax:             {"broken.txt"},
+		LintUnusedVariable:     {""},
+	}, codes)
+	require.Equal(t, 4, report.Count(LintError))
+	require.Equal(t, 1, report.Count(LintWarning))
+
+	for _, issue := range report.Issues {
+		switch issue.Code {
+		case LintUndeclaredVariable:
+			require.Equal(t, 2, issue.Line)
+			require.Contains(t, issue.Message, `"owner"`)
+		case LintUnusedVariable:
+			require.Contains(t, issue.Message, `"unused"`)
+		case LintSyntax:
+			require.Equal(t, 3, issue.Line)
+		}
+	}
+}
+
+func TestServiceLintNestedValues(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := writeTemplateFiles(t, map[string]string{
+		"template.yaml": "name: demo\n",
+		"config.yaml.tmpl": "host: {{ .database.host }}\n" +
+			"{{ range .services }}- {{ .name }}\n{{ end }}" +
+			"{{ .cache.ttl }}\n",
+	})
+	meta := &models.TemplateMetadata{
+		Name:      "demo",
+		Variables: []models.TemplateVariable{{Key: "services", Type: models.VariableTypeList}, {Key: "database.host"}},
+		Defaults:  map[string]any{"metrics": map[string]any{"port": 9090}},
+	}
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(meta, dir, nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	report, err := service.Lint(context.Background(), "demo")
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 2)
+	require.Equal(t, LintUnusedVariable, report.Issues[0].Code)
+	require.Contains(t, report.Issues[0].Message, `"metrics"`)
+	require.Equal(t, LintUndeclaredVariable, report.Issues[1].Code)
+	require.Contains(t, report.Issues[1].Message, `"cache.ttl"`)
+}
+
+func TestServiceLintComputed(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := writeTemplateFiles(t, map[string]string{
+		"template.yaml":  "name: demo\n",
+		"README.md.tmpl": "{{ .binary_name }}\n",
+	})
+	meta := &models.TemplateMetadata{
+		Name:      "demo",
+		Variables: []models.TemplateVariable{{Key: "service_name"}},
+		Computed: map[string]string{
+			"binary_name": "{{ kebab .service_name }}",
+			"a":           "{{ .b }}",
+			"b":           "{{ .a }}",
+		},
+	}
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(meta, dir, nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	report, err := service.Lint(context.Background(), "demo")
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	require.Equal(t, LintInvalidComputed, report.Issues[0].Code)
+	require.Contains(t, report.Issues[0].Message, "a -> b -> a")
+}
@@ -0,0 +1,265 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/pkg/semver"
+)
+
+// ListSort define a ordenação da listagem.
+type ListSort string
+
+// Ordenações aceitas em ListOptions.Sort. ListSortVersion põe as versões mais
+// recentes primeiro; ListSortRelevance ordena pela pontuação da busca.
+const (
+	ListSortName      ListSort = "name"
+	ListSortVersion   ListSort = "version"
+	ListSortRelevance ListSort = "relevance"
+)
+
+// ListSorts lista as ordenações na ordem exibida pela CLI.
+var ListSorts = []ListSort{ListSortName, ListSortVersion, ListSortRelevance}
+
+// ListOptions filtra e ordena a listagem de templates.
+type ListOptions struct {
+	// Tags são grupos de tags: o template precisa ter ao menos uma tag de cada
+	// grupo. Um grupo com várias tags equivale a OR; vários grupos, a AND.
+	Tags [][]string
+	// Search busca, sem diferenciar maiúsculas, cada termo em name, display_name
+	// e description, aceitando os caracteres do termo fora de sequência contígua.
+	Search string
+	// Sort vazio ordena por relevância quando há busca e por nome caso contrário.
+	Sort ListSort
+	// AllVersions inclui uma entrada para cada versão disponível de cada
+	// template, em vez de apenas a atual.
+	AllVersions bool
+}
+
+// ListResult traz os templates que atendem aos filtros e os que foram omitidos
+// por metadados inválidos.
+type ListResult struct {
+	Templates []models.TemplateMetadata
+	Skipped   []models.SkippedTemplate
+}
+
+// List retorna os templates disponíveis filtrados e ordenados. Templates com
+// template.yaml inválido não interrompem a listagem: ficam em Skipped.
+func (s *Service) List(ctx context.Context, opts ListOptions) (*ListResult, error) {
+	sortBy, err := parseListSort(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := s.repo.ListTemplates(ctx)
+	result := &ListResult{Templates: []models.TemplateMetadata{}}
+	var partial *models.PartialListError
+	switch {
+	case errors.As(err, &partial):
+		result.Skipped = partial.Skipped
+	case err != nil:
+		return nil, err
+	}
+	if opts.AllVersions {
+		if templates, err = s.expandVersions(ctx, templates, result); err != nil {
+			return nil, err
+		}
+	}
+
+	terms := strings.Fields(opts.Search)
+	var scores []int
+	for _, tmpl := range templates {
+		if !matchTags(tmpl.Tags, opts.Tags) {
+			continue
+		}
+		score, ok := searchScore(tmpl, terms)
+		if !ok {
+			continue
+		}
+		scores = append(scores, score)
+		result.Templates = append(result.Templates, tmpl)
+	}
+
+	sort.Stable(listOrder{templates: result.Templates, scores: scores, sortBy: sortBy})
+	return result, nil
+}
+
+// expandVersions acrescenta, após cada template, as demais versões disponíveis.
+// Versões que não podem ser carregadas vão para result.Skipped como
+// <nome>@<versão>.
+func (s *Service) expandVersions(ctx context.Context, templates []models.TemplateMetadata, result *ListResult) ([]models.TemplateMetadata, error) {
+	var expanded []models.TemplateMetadata
+	for _, tmpl := range templates {
+		expanded = append(expanded, tmpl)
+		versions, err := s.Versions(ctx, tmpl.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, version := range versions {
+			if semver.Equal(version, tmpl.Version) {
+				continue
+			}
+			meta, _, err := s.repo.LoadTemplateVersion(ctx, tmpl.Name, version)
+			if err != nil {
+				result.Skipped = append(result.Skipped, models.SkippedTemplate{Name: tmpl.Name + "@" + version, Reason: err.Error()})
+				continue
+			}
+			expanded = append(expanded, *meta)
+		}
+	}
+	return expanded, nil
+}
+
+// listOrder ordena templates pelo critério escolhido, desempatando pelo nome e,
+// entre versões do mesmo template, pela mais recente.
+type listOrder struct {
+	templates []models.TemplateMetadata
+	scores    []int
+	sortBy    ListSort
+}
+
+func (o listOrder) Len() int { return len(o.templates) }
+
+func (o listOrder) Swap(i, j int) {
+	o.templates[i], o.templates[j] = o.templates[j], o.templates[i]
+	o.scores[i], o.scores[j] = o.scores[j], o.scores[i]
+}
+
+func (o listOrder) Less(i, j int) bool {
+	a, b := o.templates[i], o.templates[j]
+	switch o.sortBy {
+	case ListSortVersion:
+		if c := compareVersions(a.Version, b.Version); c != 0 {
+			return c > 0
+		}
+	case ListSortRelevance:
+		if o.scores[i] != o.scores[j] {
+			return o.scores[i] > o.scores[j]
+		}
+	}
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return compareVersions(a.Version, b.Version) > 0
+}
+
+func parseListSort(opts ListOptions) (ListSort, error) {
+	if opts.Sort == "" {
+		if strings.TrimSpace(opts.Search) != "" {
+			return ListSortRelevance, nil
+		}
+		return ListSortName, nil
+	}
+	for _, sortBy := range ListSorts {
+		if sortBy == opts.Sort {
+			return sortBy, nil
+		}
+	}
+	names := make([]string, len(ListSorts))
+	for i, sortBy := range ListSorts {
+		names[i] = string(sortBy)
+	}
+	return "", fmt.Errorf("invalid sort %q (use %s)", opts.Sort, strings.Join(names, ", "))
+}
+
+// matchTags indica se tags tem ao menos uma tag de cada grupo.
+func matchTags(tags []string, groups [][]string) bool {
+	for _, group := range groups {
+		found := false
+		for _, want := range group {
+			for _, tag := range tags {
+				if strings.EqualFold(tag, want) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// searchScore soma a melhor pontuação de cada termo entre os campos do
+// template; o nome pesa mais que display_name, que pesa mais que a descrição.
+// ok é false se algum termo não casa com nenhum campo.
+func searchScore(tmpl models.TemplateMetadata, terms []string) (int, bool) {
+	total := 0
+	for _, term := range terms {
+		best, found := 0, false
+		for _, field := range []struct {
+			text   string
+			weight int
+		}{
+			{tmpl.Name, 3},
+			{tmpl.DisplayName, 2},
+			{tmpl.Description, 1},
+		} {
+			if score, ok := fuzzyScore(term, field.text); ok && (!found || score*field.weight > best) {
+				best, found = score*field.weight, true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// fuzzyScore pontua text para o termo query sem diferenciar maiúsculas. Uma
+// ocorrência contígua pontua mais quanto mais cedo aparece; caso contrário, os
+// caracteres do termo precisam aparecer em ordem em text, e letras seguidas ou
+// no início de palavras valem mais.
+func fuzzyScore(query, text string) (int, bool) {
+	lowerQuery, lowerText := strings.ToLower(query), strings.ToLower(text)
+	if lowerQuery == "" {
+		return 0, true
+	}
+	if idx := strings.Index(lowerText, lowerQuery); idx >= 0 {
+		return 1000 - min(utf8.RuneCountInString(lowerText[:idx]), 500), true
+	}
+
+	q, t := []rune(lowerQuery), []rune(lowerText)
+	score, qi, last := 0, 0, -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score += 10
+		if ti == last+1 {
+			score += 15
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 10
+		}
+		last = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return min(score, 499), true
+}
+
+// compareVersions compara versões semânticas; versões inválidas ou ausentes
+// ficam depois das válidas.
+func compareVersions(a, b string) int {
+	va, errA := semver.Parse(a)
+	vb, errB := semver.Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return va.Compare(vb)
+}
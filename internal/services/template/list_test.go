@@ -0,0 +1,76 @@
+package template
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+func TestServiceListFilters(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().ListTemplates(gomock.Any()).Return([]models.TemplateMetadata{
+		{Name: "mcp", DisplayName: "MCP Ultra", Description: "Servidor MCP completo", Version: "1.2.0", Tags: []string{"go", "grpc"}},
+		{Name: "cli-tool", DisplayName: "CLI", Description: "Ferramenta de linha de comando", Version: "2.0.0", Tags: []string{"go", "cli"}},
+		{Name: "web", DisplayName: "Web App", Description: "Frontend com servidor HTTP", Version: "v1.10.0", Tags: []string{"ts", "http"}},
+		{Name: "draft", Description: "Rascunho", Tags: []string{"Go"}},
+	}, &models.PartialListError{Skipped: []models.SkippedTemplate{{Name: "broken", Reason: "unmarshal metadata"}}}).AnyTimes()
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, zerolog.Nop(), prometheus.NewRegistry(), mockRepo)
+
+	names := func(opts ListOptions) []string {
+		t.Helper()
+		result, err := service.List(context.Background(), opts)
+		require.NoError(t, err)
+		require.Equal(t, []models.SkippedTemplate{{Name: "broken", Reason: "unmarshal metadata"}}, result.Skipped)
+		var out []string
+		for _, tmpl := range result.Templates {
+			out = append(out, tmpl.Name)
+		}
+		return out
+	}
+
+	require.Equal(t, []string{"cli-tool", "draft", "mcp", "web"}, names(ListOptions{}))
+	require.Equal(t, []string{"cli-tool", "draft", "mcp"}, names(ListOptions{Tags: [][]string{{"go"}}}), "tags sem diferenciar maiúsculas")
+	require.Equal(t, []string{"mcp"}, names(ListOptions{Tags: [][]string{{"go"}, {"grpc"}}}), "grupos são AND")
+	require.Equal(t, []string{"mcp", "web"}, names(ListOptions{Tags: [][]string{{"grpc", "http"}}}), "tags do grupo são OR")
+
+	require.Equal(t, []string{"cli-tool", "web", "mcp", "draft"}, names(ListOptions{Sort: ListSortVersion}), "semver, inválidas por último")
+	require.Equal(t, []string{"mcp", "web"}, names(ListOptions{Search: "servidor"}))
+	require.Equal(t, []string{"cli-tool"}, names(ListOptions{Search: "cli linha"}), "todos os termos precisam casar")
+	require.Equal(t, []string{"mcp"}, names(ListOptions{Search: "mcpult"}), "subsequência em display_name")
+	require.Equal(t, []string{"web", "mcp", "cli-tool"}, names(ListOptions{Search: "com"}), "ocorrências mais cedo primeiro")
+	require.Equal(t, []string{"cli-tool", "mcp", "web"}, names(ListOptions{Search: "com", Sort: ListSortName}))
+	require.Empty(t, names(ListOptions{Search: "kafka"}))
+
+	_, err := service.List(context.Background(), ListOptions{Sort: "size"})
+	require.ErrorContains(t, err, `invalid sort "size"`)
+}
+
+func TestFuzzyScore(t *testing.T) {
+	t.Parallel()
+
+	prefix, ok := fuzzyScore("mcp", "mcp-ultra")
+	require.True(t, ok)
+	inner, ok := fuzzyScore("ultra", "mcp-ultra")
+	require.True(t, ok)
+	spread, ok := fuzzyScore("mu", "mcp-ultra")
+	require.True(t, ok)
+	require.Greater(t, prefix, inner)
+	require.Greater(t, inner, spread)
+
+	_, ok = fuzzyScore("um", "mcp-ultra")
+	require.False(t, ok)
+}
@@ -0,0 +1,182 @@
+package template
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/version"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+)
+
+// LockfileName é o arquivo de proveniência gravado na raiz de cada projeto gerado.
+const LockfileName = ".mcp-template.lock"
+
+// MaskedValue substitui valores sensíveis no lockfile, em relatórios, em logs
+// e na exibição do modo interativo.
+const MaskedValue = "********"
+
+const lockfileHeader = "# Gerado por mcp-templates. Não edite manualmente.\n"
+
+// secretKeyHints identifica, pelo nome, variáveis cujo valor não deve ser persistido.
+var secretKeyHints = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "private_key", "credential"}
+
+// ReadLockfile lê o lockfile de um projeto gerado.
+func ReadLockfile(dir string) (*models.Lockfile, error) {
+	data, err := os.ReadFile(filepath.Join(dir, LockfileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("lockfile not found in %s", dir)
+		}
+		return nil, fmt.Errorf("read lockfile: %w", err)
+	}
+
+	var lock models.Lockfile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("unmarshal lockfile: %w", err)
+	}
+	return &lock, nil
+}
+
+// writeLockfile grava o lockfile do projeto em dir.
+func writeLockfile(dir string, lock *models.Lockfile) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("marshal lockfile: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, LockfileName), append([]byte(lockfileHeader), data...), 0o644); err != nil {
+		return fmt.Errorf("write lockfile: %w", err)
+	}
+	return nil
+}
+
+// newLockfile monta o lockfile a partir do job resolvido e da árvore renderizada.
+// Arquivos cujo conteúdo inclui segredos têm o hash substituído por MaskedValue,
+// já que o hash permitiria confirmar um valor adivinhado.
+func newLockfile(job *renderJob, tree *pkgtemplate.Tree) (*models.Lockfile, error) {
+	sourceHash, err := hashDirectory(job.layers...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]string, len(tree.Files))
+	for i := range tree.Files {
+		file := &tree.Files[i]
+		if file.Origin == "" && job.secrets.contains(string(file.Content)) {
+			files[file.Path] = MaskedValue
+			continue
+		}
+		sum, err := file.Digest()
+		if err != nil {
+			return nil, err
+		}
+		files[file.Path] = "sha256:" + sum
+	}
+
+	var runtime *models.RuntimeState
+	if job.opts.Runtime != nil {
+		runtime = &models.RuntimeState{Now: job.opts.Runtime.Now, Seed: job.opts.Runtime.Seed}
+	}
+
+	return &models.Lockfile{
+		Template:    job.meta.Name,
+		Version:     job.meta.Version,
+		SourceHash:  sourceHash,
+		CLIVersion:  version.Version,
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		Profile:     job.profile,
+		Values:      job.secrets.mask(job.values, true),
+		Files:       files,
+		Runtime:     runtime,
+	}, nil
+}
+
+// rehashFiles recalcula os hashes de lock.Files a partir dos arquivos gravados
+// em dir, para que reflitam alterações feitas por hooks como gofmt. Arquivos
+// removidos por hooks saem do lockfile; os que passaram a conter segredos são
+// mascarados como em newLockfile.
+func rehashFiles(lock *models.Lockfile, dir string, secrets *secrets) error {
+	files := make(map[string]string, len(lock.Files))
+	for path := range lock.Files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("hash generated file: %w", err)
+		}
+		if secrets.contains(string(data)) {
+			files[path] = MaskedValue
+			continue
+		}
+		sum := sha256.Sum256(data)
+		files[path] = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	lock.Files = files
+	return nil
+}
+
+func isSecretKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, hint := range secretKeyHints {
+		if strings.Contains(lower, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// hashDirectory calcula um SHA-256 estável do conteúdo do template: caminhos
+// relativos em ordem lexical seguidos do conteúdo de cada arquivo. Com herança,
+// as camadas são incluídas em ordem.
+func hashDirectory(roots ...string) (string, error) {
+	h := sha256.New()
+	for _, root := range roots {
+		if err := hashInto(h, root); err != nil {
+			return "", fmt.Errorf("hash template source: %w", err)
+		}
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashInto(h io.Writer, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		_, err = h.Write([]byte{0})
+		return err
+	})
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,146 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+	"github.com/vertikon/mcp-ultra-templates/internal/version"
+)
+
+func TestServiceRenderWritesLockfile(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	meta := &models.TemplateMetadata{Name: "demo", Version: "1.2.0"}
+
+	templateDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "template.yaml"), []byte("name: demo\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "app.txt.tmpl"), []byte("hello {{ .name }}\n"), 0o644))
+
+	mockRepo.EXPECT().
+		LoadTemplate(gomock.Any(), "demo").
+		Return(meta, templateDir, nil).
+		Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	outputDir := t.TempDir()
+	_, err := service.Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    outputDir,
+		Values:       map[string]any{"name": "ultra", "db_password": "hunter2"},
+	})
+	require.NoError(t, err)
+
+	lock, err := ReadLockfile(outputDir)
+	require.NoError(t, err)
+
+	sourceHash, err := hashDirectory(templateDir)
+	require.NoError(t, err)
+
+	require.Equal(t, "demo", lock.Template)
+	require.Equal(t, "1.2.0", lock.Version)
+	require.Equal(t, version.Version, lock.CLIVersion)
+	require.Equal(t, sourceHash, lock.SourceHash)
+	require.False(t, lock.GeneratedAt.IsZero())
+	require.Equal(t, map[string]any{"name": "ultra", "db_password": MaskedValue}, lock.Values)
+	require.Equal(t, map[string]string{"app.txt": hashBytes([]byte("hello ultra\n"))}, lock.Files)
+	require.Nil(t, lock.Runtime)
+}
+
+func TestServiceRenderRecordsRuntime(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	meta := &models.TemplateMetadata{Name: "demo", RuntimeFuncs: true}
+	templateDir := writeTemplateFiles(t, map[string]string{
+		"id.txt": "{{ now.Format \"2006-01-02\" }} {{ uuid }}\n",
+	})
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(meta, templateDir, nil).Times(3)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	render := func(now time.Time, seed int64) (string, *models.Lockfile) {
+		outputDir := t.TempDir()
+		_, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: outputDir, Now: now, Seed: seed})
+		require.NoError(t, err)
+		data, err := os.ReadFile(filepath.Join(outputDir, "id.txt"))
+		require.NoError(t, err)
+		lock, err := ReadLockfile(outputDir)
+		require.NoError(t, err)
+		return string(data), lock
+	}
+
+	frozen := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	first, lock := render(frozen, 7)
+	require.Equal(t, &models.RuntimeState{Now: frozen, Seed: 7}, lock.Runtime)
+	require.Regexp(t, "^2025-01-10 [0-9a-f-]{36}\n$", first)
+
+	again, _ := render(frozen, 7)
+	require.Equal(t, first, again)
+
+	_, lock = render(time.Time{}, 0)
+	require.NotNil(t, lock.Runtime)
+	require.NotZero(t, lock.Runtime.Seed)
+	require.False(t, lock.Runtime.Now.IsZero())
+}
+
+func TestHashDirectoryChangesWithContent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+
+	first, err := hashDirectory(dir)
+	require.NoError(t, err)
+	again, err := hashDirectory(dir)
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("b"), 0o644))
+	changed, err := hashDirectory(dir)
+	require.NoError(t, err)
+	require.NotEqual(t, first, changed)
+}
+
+func TestReadLockfileMissing(t *testing.T) {
+	t.Parallel()
+
+	_, err := ReadLockfile(t.TempDir())
+	require.ErrorContains(t, err, "lockfile not found")
+}
+
+func TestMaskSecretsNested(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]any{
+		"database": map[string]any{"host": "db", "password": "s3cret"},
+		"services": []any{"users"},
+	}
+	var heuristic *secrets
+	masked := heuristic.mask(values, false)
+
+	require.Equal(t, map[string]any{
+		"database": map[string]any{"host": "db", "password": MaskedValue},
+		"services": []any{"users"},
+	}, masked)
+	require.Equal(t, "s3cret", values["database"].(map[string]any)["password"])
+}
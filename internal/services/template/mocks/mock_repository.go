@@ -64,3 +64,34 @@ func (mr *MockRepositoryMockRecorder) LoadTemplate(ctx, name interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTemplate", reflect.TypeOf((*MockRepository)(nil).LoadTemplate), ctx, name)
 }
 
+// LoadTemplateVersion define o mock.
+func (m *MockRepository) LoadTemplateVersion(ctx context.Context, name, version string) (*models.TemplateMetadata, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadTemplateVersion", ctx, name, version)
+	ret0, _ := ret[0].(*models.TemplateMetadata)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LoadTemplateVersion expectation.
+func (mr *MockRepositoryMockRecorder) LoadTemplateVersion(ctx, name, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTemplateVersion", reflect.TypeOf((*MockRepository)(nil).LoadTemplateVersion), ctx, name, version)
+}
+
+// ListVersions define o mock.
+func (m *MockRepository) ListVersions(ctx context.Context, name string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", ctx, name)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions expectation.
+func (mr *MockRepositoryMockRecorder) ListVersions(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockRepository)(nil).ListVersions), ctx, name)
+}
+
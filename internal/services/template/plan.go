@@ -0,0 +1,200 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/pkg/diff"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+)
+
+// PlanAction descreve o que a renderização faria com um arquivo.
+type PlanAction string
+
+// Ações possíveis em um plano de renderização.
+const (
+	PlanCreate    PlanAction = "create"
+	PlanOverwrite PlanAction = "overwrite"
+	PlanSkip      PlanAction = "skip"
+	PlanDelete    PlanAction = "delete"
+	// Ações de um arquivo existente que difere do template com OnConflict.
+	PlanKeep     PlanAction = "keep"
+	PlanBackup   PlanAction = "backup"
+	PlanConflict PlanAction = "conflict"
+)
+
+// conflictActions traduz o modo de conflito para a ação do plano; prompt e
+// fail ficam como conflito.
+var conflictActions = map[ConflictMode]PlanAction{
+	ConflictSkip:      PlanKeep,
+	ConflictOverwrite: PlanOverwrite,
+	ConflictBackup:    PlanBackup,
+	ConflictPrompt:    PlanConflict,
+	ConflictFail:      PlanConflict,
+}
+
+// PlanOptions controla o nível de detalhe do plano.
+type PlanOptions struct {
+	// Diff inclui um diff unificado para arquivos sobrescritos ou removidos,
+	// com os segredos ocultados.
+	Diff bool
+}
+
+// PlannedFile é uma entrada do plano.
+type PlannedFile struct {
+	Path         string     `json:"path"`
+	Action       PlanAction `json:"action"`
+	Size         int64      `json:"size"`
+	ExistingSize int64      `json:"existing_size,omitempty"`
+	Diff         string     `json:"diff,omitempty"`
+}
+
+// Plan descreve o resultado de uma renderização sem executá-la.
+type Plan struct {
+	Template models.TemplateMetadata `json:"template"`
+	Output   string                  `json:"output"`
+	Files    []PlannedFile           `json:"files"`
+	Summary  map[PlanAction]int      `json:"summary"`
+}
+
+// Plan avalia carregamento, valores, validação, nomes e conteúdos exatamente como
+// Render, mas apenas relata o que seria criado, sobrescrito, mantido ou removido.
+// Com OnConflict, os arquivos que diferem trazem a ação do modo escolhido.
+func (s *Service) Plan(ctx context.Context, req RenderRequest, opts PlanOptions) (*Plan, error) {
+	if req.TemplateName == "" {
+		return nil, errors.New("template name is required")
+	}
+	if req.OutputDir == "" {
+		return nil, errors.New("output directory is required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.OperationTimeout)
+	defer cancel()
+
+	job, err := s.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateHooks(job.meta.Hooks, req.AllowHooks); err != nil {
+		return nil, err
+	}
+
+	policy, err := newConflictPolicy(req)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := checkOutput(req.OutputDir, req.Overwrite || policy != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := pkgtemplate.BuildTree(ctx, job.templatePath, job.values, job.opts)
+	if err != nil {
+		return nil, fmt.Errorf("render template: %w", err)
+	}
+
+	existing := map[string]int64{}
+	if exists {
+		if existing, err = existingFiles(req.OutputDir); err != nil {
+			return nil, err
+		}
+		// O lockfile é sempre regravado e não faz parte do plano.
+		delete(existing, LockfileName)
+	}
+
+	plan := &Plan{
+		Template: *job.meta,
+		Output:   req.OutputDir,
+		Summary:  map[PlanAction]int{},
+	}
+
+	for i := range tree.Files {
+		file := &tree.Files[i]
+		entry := PlannedFile{Path: file.Path, Action: PlanCreate, Size: file.Size}
+		if size, ok := existing[file.Path]; ok {
+			delete(existing, file.Path)
+			entry.ExistingSize = size
+			current, err := os.ReadFile(filepath.Join(req.OutputDir, filepath.FromSlash(file.Path)))
+			if err != nil {
+				return nil, fmt.Errorf("read existing file: %w", err)
+			}
+			content, err := file.Bytes()
+			if err != nil {
+				return nil, err
+			}
+			if bytes.Equal(current, content) {
+				entry.Action = PlanSkip
+			} else {
+				entry.Action = PlanOverwrite
+				if policy != nil {
+					entry.Action = conflictActions[policy.mode]
+				}
+				if opts.Diff {
+					entry.Diff = job.secrets.redact(diff.Unified("a/"+file.Path, "b/"+file.Path, current, content))
+				}
+			}
+		}
+		plan.Files = append(plan.Files, entry)
+	}
+
+	// Com OnConflict, arquivos que não vêm do template são preservados.
+	if policy != nil {
+		existing = nil
+	}
+	for path, size := range existing {
+		entry := PlannedFile{Path: path, Action: PlanDelete, ExistingSize: size}
+		if opts.Diff {
+			current, err := os.ReadFile(filepath.Join(req.OutputDir, filepath.FromSlash(path)))
+			if err != nil {
+				return nil, fmt.Errorf("read existing file: %w", err)
+			}
+			entry.Diff = job.secrets.redact(diff.Unified("a/"+path, "/dev/null", current, nil))
+		}
+		plan.Files = append(plan.Files, entry)
+	}
+
+	sort.Slice(plan.Files, func(i, j int) bool { return plan.Files[i].Path < plan.Files[j].Path })
+	for _, file := range plan.Files {
+		plan.Summary[file.Action]++
+	}
+	return plan, nil
+}
+
+// existingFiles lista os arquivos já presentes no diretório de saída com seus
+// tamanhos. Os diretórios de vcsDirs ficam de fora, já que a renderização nunca
+// os altera.
+func existingFiles(root string) (map[string]int64, error) {
+	files := make(map[string]int64)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && vcsDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan output dir: %w", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,116 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+func TestServicePlan(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	meta := &models.TemplateMetadata{Name: "demo", Defaults: map[string]any{"name": "svc"}}
+
+	templateDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "new.txt.tmpl"), []byte("hello {{ .name }}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "same.txt"), []byte("same\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "changed.txt.tmpl"), []byte("name: {{ .name }}\n"), 0o644))
+
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "same.txt"), []byte("same\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "changed.txt"), []byte("name: old\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(outputDir, "local"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "local", "notes.md"), []byte("mine\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(outputDir, ".git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0o644))
+
+	mockRepo.EXPECT().
+		LoadTemplate(gomock.Any(), "demo").
+		Return(meta, templateDir, nil).
+		Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	plan, err := service.Plan(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    outputDir,
+		Overwrite:    true,
+	}, PlanOptions{Diff: true})
+	require.NoError(t, err)
+
+	actions := map[string]PlanAction{}
+	for _, file := range plan.Files {
+		actions[file.Path] = file.Action
+	}
+	require.Equal(t, map[string]PlanAction{
+		"changed.txt":    PlanOverwrite,
+		"local/notes.md": PlanDelete,
+		"new.txt":        PlanCreate,
+		"same.txt":       PlanSkip,
+	}, actions)
+	require.Equal(t, map[PlanAction]int{PlanCreate: 1, PlanOverwrite: 1, PlanSkip: 1, PlanDelete: 1}, plan.Summary)
+
+	require.Equal(t, "changed.txt", plan.Files[0].Path)
+	require.Contains(t, plan.Files[0].Diff, "-name: old\n+name: svc\n")
+	require.Equal(t, int64(len("hello svc\n")), plan.Files[2].Size)
+
+	_, err = os.Stat(filepath.Join(outputDir, "new.txt"))
+	require.True(t, os.IsNotExist(err))
+	require.FileExists(t, filepath.Join(outputDir, "local", "notes.md"))
+}
+
+func TestServicePlanNonEmptyWithoutOverwrite(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	templateDir := t.TempDir()
+	mockRepo.EXPECT().
+		LoadTemplate(gomock.Any(), "demo").
+		Return(&models.TemplateMetadata{Name: "demo"}, templateDir, nil).
+		Times(1)
+
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "existing.txt"), []byte("x"), 0o644))
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	_, err := service.Plan(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: outputDir}, PlanOptions{})
+	require.ErrorContains(t, err, "not empty")
+}
+
+func TestServicePlanValidatesHooks(t *testing.T) {
+	t.Parallel()
+
+	meta := &models.TemplateMetadata{
+		Name:  "demo",
+		Hooks: models.Hooks{PostRender: []models.HookStep{{Name: "seed", Action: models.HookCommand, Command: []string{"make", "seed"}}}},
+	}
+	service := hookService(t, meta, writeTemplateFiles(t, map[string]string{"README.md": "demo\n"}))
+
+	req := RenderRequest{TemplateName: "demo", OutputDir: filepath.Join(t.TempDir(), "out")}
+	_, err := service.Plan(context.Background(), req, PlanOptions{})
+	require.ErrorContains(t, err, "command hooks require --allow-hooks")
+
+	req.AllowHooks = true
+	_, err = service.Plan(context.Background(), req, PlanOptions{})
+	require.NoError(t, err)
+}
@@ -0,0 +1,70 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+)
+
+// pathRules converte as regras de template.yaml validando padrões e condições
+// antes que qualquer arquivo do diretório de saída seja tocado.
+func pathRules(meta *models.TemplateMetadata) ([]pkgtemplate.PathRule, error) {
+	rules := make([]pkgtemplate.PathRule, 0, len(meta.Rules))
+	for i, rule := range meta.Rules {
+		include := strings.TrimSpace(rule.Include)
+		exclude := strings.TrimSpace(rule.Exclude)
+		if (include == "") == (exclude == "") {
+			return nil, fmt.Errorf("rules[%d]: exactly one of include or exclude must be set", i)
+		}
+
+		converted := pkgtemplate.PathRule{Pattern: include, When: rule.When}
+		if exclude != "" {
+			converted.Pattern = exclude
+			converted.Exclude = true
+		}
+		if !pkgtemplate.ValidGlob(converted.Pattern) {
+			return nil, fmt.Errorf("rules[%d]: invalid pattern %q", i, converted.Pattern)
+		}
+		if strings.TrimSpace(rule.When) != "" {
+			if _, err := pkgtemplate.ParseCondition(rule.When); err != nil {
+				return nil, fmt.Errorf("rules[%d]: %w", i, err)
+			}
+		}
+		rules = append(rules, converted)
+	}
+	return rules, nil
+}
+
+// delimiterRules converte e valida os delimitadores e os padrões de copy_only e
+// render_only declarados em template.yaml.
+func delimiterRules(meta *models.TemplateMetadata) ([]pkgtemplate.DelimiterRule, error) {
+	rules := make([]pkgtemplate.DelimiterRule, 0, len(meta.Delimiters))
+	for i, delims := range meta.Delimiters {
+		left, right := strings.TrimSpace(delims.Left), strings.TrimSpace(delims.Right)
+		if left == "" || right == "" {
+			return nil, fmt.Errorf("delimiters[%d]: left and right are required", i)
+		}
+		if left == right {
+			return nil, fmt.Errorf("delimiters[%d]: left and right must differ", i)
+		}
+		for _, pattern := range delims.Paths {
+			if !pkgtemplate.ValidGlob(pattern) {
+				return nil, fmt.Errorf("delimiters[%d]: invalid pattern %q", i, pattern)
+			}
+		}
+		rules = append(rules, pkgtemplate.DelimiterRule{Left: left, Right: right, Patterns: delims.Paths})
+	}
+	for _, pattern := range meta.CopyOnly {
+		if !pkgtemplate.ValidGlob(pattern) {
+			return nil, fmt.Errorf("copy_only: invalid pattern %q", pattern)
+		}
+	}
+	for _, pattern := range meta.RenderOnly {
+		if !pkgtemplate.ValidGlob(pattern) {
+			return nil, fmt.Errorf("render_only: invalid pattern %q", pattern)
+		}
+	}
+	return rules, nil
+}
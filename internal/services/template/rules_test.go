@@ -0,0 +1,64 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+)
+
+func TestPathRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := pathRules(&models.TemplateMetadata{
+		Rules: []models.FileRule{
+			{Exclude: "internal/grpc/**", When: "enable_grpc == false"},
+			{Include: "internal/grpc/README.md"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []pkgtemplate.PathRule{
+		{Pattern: "internal/grpc/**", Exclude: true, When: "enable_grpc == false"},
+		{Pattern: "internal/grpc/README.md"},
+	}, rules)
+}
+
+func TestPathRulesInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []models.FileRule{
+		{},
+		{Include: "a", Exclude: "b"},
+		{Exclude: "a/[b"},
+		{Exclude: "a/**", When: "x =="},
+	}
+	for _, rule := range cases {
+		_, err := pathRules(&models.TemplateMetadata{Rules: []models.FileRule{rule}})
+		require.Error(t, err, "%+v", rule)
+	}
+}
+
+func TestDelimiterRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := delimiterRules(&models.TemplateMetadata{
+		Delimiters: []models.Delimiters{{Left: "[[", Right: "]]", Paths: []string{"**/*.go"}}},
+		CopyOnly:   []string{"deploy/helm/**"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []pkgtemplate.DelimiterRule{{Left: "[[", Right: "]]", Patterns: []string{"**/*.go"}}}, rules)
+
+	invalid := []*models.TemplateMetadata{
+		{Delimiters: []models.Delimiters{{Left: "[["}}},
+		{Delimiters: []models.Delimiters{{Left: "%", Right: "%"}}},
+		{Delimiters: []models.Delimiters{{Left: "[[", Right: "]]", Paths: []string{"a/[b"}}}},
+		{CopyOnly: []string{"a/[b"}},
+		{RenderOnly: []string{"a/[b"}},
+	}
+	for _, meta := range invalid {
+		_, err := delimiterRules(meta)
+		require.Error(t, err, "%+v", meta)
+	}
+}
@@ -0,0 +1,210 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// Referências aceitas como valor de variáveis secretas, resolvidas antes da
+// validação para que o segredo não precise passar pela linha de comando.
+const (
+	SecretFromEnv   = "env:"
+	SecretFromFile  = "file:"
+	SecretFromStdin = "stdin"
+)
+
+// IsSecretReference informa se value é uma referência env:, file: ou stdin,
+// resolvida apenas para variáveis secretas.
+func IsSecretReference(value string) bool {
+	return strings.HasPrefix(value, SecretFromEnv) || strings.HasPrefix(value, SecretFromFile) || value == SecretFromStdin
+}
+
+// minRedactLength evita que segredos muito curtos mascarem trechos comuns de
+// logs e erros; eles continuam mascarados em lockfiles e relatórios.
+const minRedactLength = 4
+
+// secrets identifica e oculta os valores sensíveis de uma renderização:
+// variáveis com secret: true, chaves cujo nome parece credencial e qualquer
+// texto que contenha o valor de uma delas. O valor zero oculta apenas pelo nome.
+type secrets struct {
+	paths map[string]bool
+	// refs guarda a referência original (env:, file:) de cada segredo resolvido.
+	refs     map[string]string
+	replacer *strings.Replacer
+}
+
+// resolveSecrets substitui referências env:, file: e stdin nas variáveis
+// secretas e retorna o conjunto de segredos resultante.
+func resolveSecrets(meta *models.TemplateMetadata, values map[string]any, stdin io.Reader) (*secrets, error) {
+	s := &secrets{paths: make(map[string]bool), refs: make(map[string]string)}
+	stdinUsed := ""
+	for _, variable := range meta.Variables {
+		if !variable.Secret {
+			continue
+		}
+		s.paths[variable.Key] = true
+
+		value, ok := valuespkg.Lookup(values, variable.Key)
+		ref, isString := value.(string)
+		if !ok || !isString {
+			continue
+		}
+
+		var resolved string
+		switch {
+		case strings.HasPrefix(ref, SecretFromEnv):
+			name := strings.TrimPrefix(ref, SecretFromEnv)
+			env, found := os.LookupEnv(name)
+			if !found {
+				return nil, fmt.Errorf("secret %s: environment variable %s is not set", variable.Key, name)
+			}
+			resolved = env
+		case strings.HasPrefix(ref, SecretFromFile):
+			path := strings.TrimPrefix(ref, SecretFromFile)
+			data, err := os.ReadFile(filepath.Clean(path))
+			if err != nil {
+				return nil, fmt.Errorf("secret %s: read %s: %w", variable.Key, path, errors.Unwrap(err))
+			}
+			resolved = trimNewline(string(data))
+		case ref == SecretFromStdin:
+			if stdinUsed != "" {
+				return nil, fmt.Errorf("secret %s: stdin already used by %s", variable.Key, stdinUsed)
+			}
+			if stdin == nil {
+				return nil, fmt.Errorf("secret %s: stdin is not available", variable.Key)
+			}
+			data, err := io.ReadAll(stdin)
+			if err != nil {
+				return nil, fmt.Errorf("secret %s: read stdin: %w", variable.Key, err)
+			}
+			stdinUsed = variable.Key
+			resolved = trimNewline(string(data))
+		default:
+			continue
+		}
+
+		if err := valuespkg.Set(values, variable.Key, resolved); err != nil {
+			return nil, fmt.Errorf("secret %s: %w", variable.Key, err)
+		}
+		if ref != SecretFromStdin {
+			s.refs[variable.Key] = ref
+		}
+	}
+
+	var plain []string
+	valuespkg.Walk(values, func(path string, value any) {
+		text := valuespkg.String(value)
+		if len(text) >= minRedactLength && s.isSecret(path) {
+			plain = append(plain, text)
+		}
+	})
+	// Segredos mais longos primeiro, para que um segredo contido em outro não
+	// deixe sobras do maior.
+	sort.Slice(plain, func(i, j int) bool { return len(plain[i]) > len(plain[j]) })
+	pairs := make([]string, 0, 2*len(plain))
+	for _, text := range plain {
+		pairs = append(pairs, text, MaskedValue)
+	}
+	if len(pairs) > 0 {
+		s.replacer = strings.NewReplacer(pairs...)
+	}
+	return s, nil
+}
+
+// isSecret informa se o valor em path é sensível.
+func (s *secrets) isSecret(path string) bool {
+	if s != nil && s.paths[path] {
+		return true
+	}
+	segments := strings.Split(path, valuespkg.Separator)
+	return isSecretKey(segments[len(segments)-1])
+}
+
+// mask copia a árvore ocultando os segredos. Com keepRefs, segredos vindos de
+// env: ou file: mantêm a referência, para que possam ser resolvidos de novo.
+func (s *secrets) mask(values map[string]any, keepRefs bool) map[string]any {
+	result, _ := s.maskValue("", values, keepRefs).(map[string]any)
+	return result
+}
+
+func (s *secrets) maskValue(path string, value any, keepRefs bool) any {
+	join := func(segment string) string {
+		if path == "" {
+			return segment
+		}
+		return path + valuespkg.Separator + segment
+	}
+	switch typed := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(typed))
+		for key, item := range typed {
+			result[key] = s.maskValue(join(key), item, keepRefs)
+		}
+		return result
+	case []any:
+		result := make([]any, len(typed))
+		for i, item := range typed {
+			result[i] = s.maskValue(join(fmt.Sprint(i)), item, keepRefs)
+		}
+		return result
+	}
+
+	if s != nil && keepRefs && s.refs[path] != "" {
+		return s.refs[path]
+	}
+	if valuespkg.String(value) != "" && s.isSecret(path) {
+		return MaskedValue
+	}
+	if text, ok := value.(string); ok {
+		return s.redact(text)
+	}
+	return value
+}
+
+// redact oculta ocorrências de segredos em texto livre, como logs e erros.
+func (s *secrets) redact(text string) string {
+	if s == nil || s.replacer == nil {
+		return text
+	}
+	return s.replacer.Replace(text)
+}
+
+// contains informa se text inclui o valor de algum segredo.
+func (s *secrets) contains(text string) bool {
+	return s.redact(text) != text
+}
+
+// redactError oculta segredos na mensagem de err, preservando a cadeia para
+// errors.Is e errors.As.
+func (s *secrets) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := s.redact(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+func trimNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
@@ -0,0 +1,185 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+func secretMetadata() *models.TemplateMetadata {
+	return &models.TemplateMetadata{
+		Name:    "demo",
+		Version: "1.0.0",
+		Variables: []models.TemplateVariable{
+			{Key: "database.password", Required: true, Secret: true},
+			{Key: "jwt_signing", Secret: true},
+			{Key: "nats_creds", Secret: true},
+		},
+		Computed: map[string]string{"dsn": "postgres://app:{{ .database.password }}@db/app"},
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("MCP_TEST_DB_PASSWORD", "pg-Secret-1")
+	credsFile := filepath.Join(t.TempDir(), "nats.creds")
+	require.NoError(t, os.WriteFile(credsFile, []byte("nats-Secret-2\n"), 0o600))
+
+	values := map[string]any{
+		"database":    map[string]any{"password": "env:MCP_TEST_DB_PASSWORD"},
+		"nats_creds":  "file:" + credsFile,
+		"jwt_signing": "stdin",
+		"note":        "uses pg-Secret-1 inline",
+	}
+	secrets, err := resolveSecrets(secretMetadata(), values, strings.NewReader("jwt-Secret-3\r\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, "pg-Secret-1", stringValue(values, "database.password"))
+	require.Equal(t, "nats-Secret-2", stringValue(values, "nats_creds"))
+	require.Equal(t, "jwt-Secret-3", stringValue(values, "jwt_signing"))
+	require.Equal(t, map[string]string{
+		"database.password": "env:MCP_TEST_DB_PASSWORD",
+		"nats_creds":        "file:" + credsFile,
+	}, secrets.refs)
+
+	require.Equal(t, "a ******** b ********", secrets.redact("a pg-Secret-1 b jwt-Secret-3"))
+	err = secrets.redactError(&HookError{Phase: PhasePostRender, Step: "seed", Err: errors.New("bad nats-Secret-2")})
+	require.NotContains(t, err.Error(), "nats-Secret-2")
+	var hookErr *HookError
+	require.ErrorAs(t, err, &hookErr)
+
+	require.Equal(t, map[string]any{
+		"database":    map[string]any{"password": "env:MCP_TEST_DB_PASSWORD"},
+		"nats_creds":  "file:" + credsFile,
+		"jwt_signing": MaskedValue,
+		"note":        "uses ******** inline",
+	}, secrets.mask(values, true))
+	require.Equal(t, MaskedValue, secrets.mask(values, false)["nats_creds"])
+}
+
+func TestResolveSecretsErrors(t *testing.T) {
+	t.Parallel()
+
+	meta := secretMetadata()
+	cases := map[string]map[string]any{
+		"environment variable MCP_TEST_UNSET_VARIABLE is not set": {"jwt_signing": "env:MCP_TEST_UNSET_VARIABLE"},
+		"read /nonexistent/creds":                                 {"nats_creds": "file:/nonexistent/creds"},
+		"stdin already used by jwt_signing":                       {"jwt_signing": "stdin", "nats_creds": "stdin"},
+	}
+	for expected, values := range cases {
+		_, err := resolveSecrets(meta, values, strings.NewReader("value"))
+		require.ErrorContains(t, err, expected)
+	}
+
+	_, err := resolveSecrets(meta, map[string]any{"jwt_signing": "stdin"}, nil)
+	require.ErrorContains(t, err, "stdin is not available")
+
+	// Valores literais e variáveis que não são secretas não são interpretados.
+	values := map[string]any{"jwt_signing": "plain-value", "other": "env:HOME"}
+	_, err = resolveSecrets(meta, values, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"jwt_signing": "plain-value", "other": "env:HOME"}, values)
+}
+
+func TestValidateValueMasksSecrets(t *testing.T) {
+	t.Parallel()
+
+	err := ValidateValue(models.TemplateVariable{Key: "token", Pattern: "[a-z]+", Secret: true}, "Sup3rSecret")
+	require.ErrorContains(t, err, MaskedValue)
+	require.NotContains(t, err.Error(), "Sup3rSecret")
+
+	err = validateTree(models.TemplateVariable{Key: "token", Secret: true}, []any{"Sup3rSecret"})
+	require.NotContains(t, err.Error(), "Sup3rSecret")
+}
+
+func TestServiceRenderSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Setenv("MCP_TEST_DB_PASSWORD", "pg-Secret-1")
+	templateDir := writeTemplateFiles(t, map[string]string{
+		".env.tmpl": "DB_PASSWORD={{ .database.password }}\nDATABASE_URL={{ .dsn }}\nJWT_SECRET={{ .jwt_signing }}\n",
+	})
+	meta := secretMetadata()
+	meta.Hooks.PostRender = []models.HookStep{{Name: "seed", Action: models.HookCommand, Command: []string{"sh", "-c", "echo using {{ .jwt_signing }}; exit 3"}}}
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(meta, templateDir, nil).AnyTimes()
+
+	var logs bytes.Buffer
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, zerolog.New(&logs), prometheus.NewRegistry(), mockRepo)
+
+	req := RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    t.TempDir(),
+		Values:       map[string]any{"database": map[string]any{"password": "env:MCP_TEST_DB_PASSWORD"}, "jwt_signing": "stdin"},
+		Stdin:        strings.NewReader("jwt-Secret-3\n"),
+		AllowHooks:   true,
+	}
+	_, err := service.Render(context.Background(), req)
+	var hookErr *HookError
+	require.ErrorAs(t, err, &hookErr)
+	require.Contains(t, hookErr.Output, "using "+MaskedValue)
+	require.NotContains(t, err.Error(), "jwt-Secret-3")
+	require.NoFileExists(t, filepath.Join(req.OutputDir, ".env"))
+
+	meta.Hooks.PostRender[0].Optional = true
+	req.Stdin = strings.NewReader("jwt-Secret-3\n")
+	_, err = service.Render(context.Background(), req)
+	require.NoError(t, err)
+	require.Contains(t, logs.String(), "using "+MaskedValue)
+	require.NotContains(t, logs.String(), "jwt-Secret-3")
+
+	data, err := os.ReadFile(filepath.Join(req.OutputDir, ".env"))
+	require.NoError(t, err)
+	require.Equal(t, "DB_PASSWORD=pg-Secret-1\nDATABASE_URL=postgres://app:pg-Secret-1@db/app\nJWT_SECRET=jwt-Secret-3\n", string(data))
+
+	lock, err := ReadLockfile(req.OutputDir)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"database":    map[string]any{"password": "env:MCP_TEST_DB_PASSWORD"},
+		"dsn":         "postgres://app:********@db/app",
+		"jwt_signing": MaskedValue,
+	}, lock.Values)
+	require.Equal(t, MaskedValue, lock.Files[".env"])
+
+	require.NoError(t, os.WriteFile(filepath.Join(req.OutputDir, ".env"), []byte("DB_PASSWORD=old\n"), 0o644))
+	req.Stdin = strings.NewReader("jwt-Secret-3\n")
+	req.OnConflict = ConflictOverwrite
+	plan, err := service.Plan(context.Background(), req, PlanOptions{Diff: true})
+	require.NoError(t, err)
+	var envDiff string
+	for _, file := range plan.Files {
+		if file.Path == ".env" {
+			envDiff = file.Diff
+		}
+	}
+	require.Contains(t, envDiff, "+JWT_SECRET="+MaskedValue)
+	require.NotContains(t, envDiff, "pg-Secret-1")
+	require.NotContains(t, envDiff, "jwt-Secret-3")
+
+	report, err := service.ExplainValues(context.Background(), "demo", "", []valuespkg.Layer{
+		{Source: "--set database.password", Values: map[string]any{"database": map[string]any{"password": "env:MCP_TEST_DB_PASSWORD"}}},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []ValueOrigin{
+		{Path: "database.password", Value: MaskedValue, Source: "--set database.password (env:MCP_TEST_DB_PASSWORD)"},
+		{Path: "dsn", Value: "postgres://app:********@db/app", Source: SourceComputed},
+	}, report.Values)
+
+}
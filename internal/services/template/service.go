@@ -4,8 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
-	"path/filepath"
 	"time"
 
 	backoff "github.com/cenkalti/backoff/v4"
@@ -16,12 +17,18 @@ import (
 	"github.com/vertikon/mcp-ultra-templates/internal/models"
 	repo "github.com/vertikon/mcp-ultra-templates/internal/repository/fs"
 	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
 )
 
 // Repository define o comportamento esperado para storage de templates.
+// ListTemplates pode retornar a listagem junto com um *models.PartialListError
+// quando alguns templates foram omitidos.
 type Repository interface {
 	ListTemplates(ctx context.Context) ([]models.TemplateMetadata, error)
 	LoadTemplate(ctx context.Context, name string) (*models.TemplateMetadata, string, error)
+	LoadTemplateVersion(ctx context.Context, name, version string) (*models.TemplateMetadata, string, error)
+	// ListVersions lista as versões disponíveis do template, sem ordem definida.
+	ListVersions(ctx context.Context, name string) ([]string, error)
 }
 
 // Service orquestra a renderização de templates utilizando repositório e métricas.
@@ -60,6 +67,12 @@ func New(cfg config.RenderingConfig, logger zerolog.Logger, registry *prometheus
 		registry.MustRegister(m.duration, m.errors, m.success)
 	}
 
+	if source, ok := repository.(staleCacheSource); ok {
+		source.OnStaleCache(func(ref string, err error) {
+			logger.Warn().Err(err).Str("ref", ref).Msg("não foi possível atualizar o repositório de templates; usando a cópia em cache, que pode estar desatualizada")
+		})
+	}
+
 	return &Service{
 		cfg:     cfg,
 		logger:  logger,
@@ -68,6 +81,12 @@ func New(cfg config.RenderingConfig, logger zerolog.Logger, registry *prometheus
 	}
 }
 
+// staleCacheSource é implementada por repositórios remotos que podem servir
+// templates do cache quando o remoto está indisponível.
+type staleCacheSource interface {
+	OnStaleCache(fn func(ref string, err error))
+}
+
 // NewDefaultRepository é um helper para criar repositório filesystem com configuração padrão.
 func NewDefaultRepository(root string) Repository {
 	return repo.New(root)
@@ -75,21 +94,57 @@ func NewDefaultRepository(root string) Repository {
 
 // RenderRequest contém os parâmetros necessários.
 type RenderRequest struct {
+	// TemplateName aceita uma versão ou restrição, como "mcp@^1.1"; veja
+	// SplitTemplateRef.
 	TemplateName string
 	OutputDir    string
-	Values       map[string]string
-	Overwrite    bool
+	Values       map[string]any
+	// Profile seleciona uma sobreposição de valores declarada em profiles.
+	Profile   string
+	Overwrite bool
+	// AllowHooks autoriza hooks com action command, que executam comandos arbitrários.
+	AllowHooks bool
+	// Now e Seed fixam now e uuid em templates com runtime_funcs; zero usa o
+	// relógio e uma semente aleatória, registrados no lockfile.
+	Now  time.Time
+	Seed int64
+	// Stdin fornece o valor de uma variável secreta definida como "stdin".
+	Stdin io.Reader
+	// OnConflict permite gerar em uma saída não vazia decidindo arquivo a
+	// arquivo, preservando os que não vêm do template. Vazio mantém o
+	// comportamento de Overwrite, com o qual é mutuamente exclusivo.
+	OnConflict ConflictMode
+	// ResolveConflict responde cada conflito quando OnConflict é ConflictPrompt.
+	ResolveConflict ConflictResolver
+	// BackupDir guarda os backups de ConflictBackup em ConflictBackupDir em vez
+	// de arquivos OrigSuffix.
+	BackupDir bool
 }
 
 // RenderResponse retorna metadados pós-renderização.
 type RenderResponse struct {
 	Template models.TemplateMetadata
 	Output   string
+	// Conflicts lista os arquivos existentes que diferiam do template e o que
+	// foi feito com eles; vazio sem OnConflict.
+	Conflicts []ResolvedConflict
 }
 
-// List retorna os templates disponíveis.
-func (s *Service) List(ctx context.Context) ([]models.TemplateMetadata, error) {
-	return s.repo.ListTemplates(ctx)
+// Template carrega os metadados de um template com a herança aplicada, incluindo
+// variáveis, defaults e perfis dos templates base.
+func (s *Service) Template(ctx context.Context, name string) (*models.TemplateMetadata, error) {
+	if name == "" {
+		return nil, errors.New("template name is required")
+	}
+	meta, templatePath, err := s.loadTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	layered, err := s.inherit(ctx, meta, templatePath)
+	if err != nil {
+		return nil, err
+	}
+	return layered.meta, nil
 }
 
 // Render aplica o template específico e gera o projeto.
@@ -105,35 +160,60 @@ func (s *Service) Render(ctx context.Context, req RenderRequest) (*RenderRespons
 	defer cancel()
 
 	start := time.Now()
-	meta, templatePath, err := s.repo.LoadTemplate(ctx, req.TemplateName)
+	job, err := s.prepare(ctx, req)
 	if err != nil {
-		s.metrics.errors.WithLabelValues(req.TemplateName, "load").Inc()
+		return nil, err
+	}
+	meta := job.meta
+	if err := validateHooks(meta.Hooks, req.AllowHooks); err != nil {
+		s.metrics.errors.WithLabelValues(req.TemplateName, "validation").Inc()
 		return nil, err
 	}
 
-	values := mergeValues(meta, req.Values)
-	if err := validateVariables(meta, values); err != nil {
+	policy, err := newConflictPolicy(req)
+	if err != nil {
 		s.metrics.errors.WithLabelValues(req.TemplateName, "validation").Inc()
 		return nil, err
 	}
+	exists, err := checkOutput(req.OutputDir, req.Overwrite || policy != nil)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(req.TemplateName, "output").Inc()
+		return nil, err
+	}
 
-	if err := s.prepareOutput(req.OutputDir, req.Overwrite); err != nil {
+	// Arquivos, lockfile e hooks são gerados em staging; a saída só muda na
+	// promoção, depois que tudo deu certo.
+	staging, err := newStage(req.OutputDir, exists)
+	if err != nil {
 		s.metrics.errors.WithLabelValues(req.TemplateName, "output").Inc()
 		return nil, err
 	}
+	defer func() {
+		if err := staging.discard(); err != nil {
+			s.logger.Warn().Err(err).Str("dir", staging.dir).Msg("não foi possível remover o diretório de staging")
+		}
+	}()
+
+	if err := s.runHooks(ctx, PhasePreRender, meta.Hooks.PreRender, staging, job.values, job.secrets); err != nil {
+		s.metrics.errors.WithLabelValues(req.TemplateName, "hook").Inc()
+		return nil, err
+	}
 
+	var tree *pkgtemplate.Tree
 	operation := func() error {
-		opts := pkgtemplate.RenderOptions{
-			IgnoredPaths: map[string]struct{}{
-				"template.yaml": {},
-			},
+		var err error
+		if tree, err = pkgtemplate.BuildTree(ctx, job.templatePath, job.values, job.opts); err == nil {
+			err = tree.Write(ctx, staging.dir)
+		}
+		if err != nil && !retryable(err) {
+			return backoff.Permanent(err)
 		}
-		return pkgtemplate.RenderDirectory(ctx, templatePath, req.OutputDir, values, opts)
+		return err
 	}
 
 	notify := func(err error, d time.Duration) {
 		s.logger.Warn().
-			Err(err).
+			Err(job.secrets.redactError(err)).
 			Dur("retry_in", d).
 			Str("template", req.TemplateName).
 			Msg("falha ao renderizar template, tentando novamente")
@@ -145,7 +225,65 @@ func (s *Service) Render(ctx context.Context, req RenderRequest) (*RenderRespons
 
 	if err := backoff.RetryNotify(operation, backoff.WithContext(backoff.WithMaxRetries(expBackoff, uint64(s.cfg.MaxRetryAttempts)), ctx), notify); err != nil {
 		s.metrics.errors.WithLabelValues(req.TemplateName, "render").Inc()
-		return nil, fmt.Errorf("render template: %w", err)
+		return nil, job.secrets.redactError(fmt.Errorf("render template: %w", err))
+	}
+
+	lock, err := newLockfile(job, tree)
+	if err == nil {
+		err = writeLockfile(staging.dir, lock)
+	}
+	if err != nil {
+		s.metrics.errors.WithLabelValues(req.TemplateName, "lockfile").Inc()
+		return nil, err
+	}
+
+	// O lockfile já existe durante os hooks; os hashes são recalculados depois
+	// deles para registrar os arquivos como ficaram na saída. Os passos a partir
+	// do primeiro git_init rodam depois dessa regravação, para que o commit
+	// inicial inclua o lockfile final.
+	post := namedSteps(meta.Hooks.PostRender)
+	initAt := len(post)
+	for i, step := range post {
+		if step.Action == models.HookGitInit {
+			initAt = i
+			break
+		}
+	}
+	for _, steps := range [][]models.HookStep{post[:initAt], post[initAt:]} {
+		if len(steps) == 0 {
+			continue
+		}
+		if err := s.runHooks(ctx, PhasePostRender, steps, staging, job.values, job.secrets); err != nil {
+			s.metrics.errors.WithLabelValues(req.TemplateName, "hook").Inc()
+			return nil, err
+		}
+		err := rehashFiles(lock, staging.dir, job.secrets)
+		if err == nil {
+			err = writeLockfile(staging.dir, lock)
+		}
+		if err != nil {
+			s.metrics.errors.WithLabelValues(req.TemplateName, "lockfile").Inc()
+			return nil, err
+		}
+	}
+
+	var (
+		conflicts []ResolvedConflict
+		backup    string
+	)
+	if policy != nil && exists {
+		conflicts, backup, err = staging.merge(policy)
+	} else {
+		backup, err = staging.promote()
+	}
+	if err != nil {
+		s.metrics.errors.WithLabelValues(req.TemplateName, "output").Inc()
+		return nil, err
+	}
+	if backup != "" {
+		if err := os.RemoveAll(backup); err != nil {
+			s.logger.Warn().Err(err).Str("dir", backup).Msg("não foi possível remover o backup da saída anterior")
+		}
 	}
 
 	elapsed := time.Since(start).Seconds()
@@ -159,60 +297,165 @@ func (s *Service) Render(ctx context.Context, req RenderRequest) (*RenderRespons
 		Msg("template renderizado com sucesso")
 
 	return &RenderResponse{
-		Template: *meta,
-		Output:   req.OutputDir,
+		Template:  *meta,
+		Output:    req.OutputDir,
+		Conflicts: conflicts,
 	}, nil
 }
 
-func mergeValues(meta *models.TemplateMetadata, values map[string]string) map[string]string {
-	result := make(map[string]string, len(meta.Defaults)+len(values))
-	for k, v := range meta.Defaults {
-		result[k] = v
+// renderJob reúne tudo que foi resolvido e validado antes de gerar arquivos.
+type renderJob struct {
+	meta         *models.TemplateMetadata
+	templatePath string
+	// layers inclui os templates herdados, do mais básico a templatePath.
+	layers  []string
+	profile string
+	values  map[string]any
+	secrets *secrets
+	opts    pkgtemplate.RenderOptions
+}
+
+// prepare carrega o template, mescla e valida valores e compila as opções de
+// renderização. Nenhum arquivo do diretório de saída é tocado.
+func (s *Service) prepare(ctx context.Context, req RenderRequest) (*renderJob, error) {
+	meta, templatePath, err := s.loadTemplate(ctx, req.TemplateName)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(req.TemplateName, "load").Inc()
+		return nil, err
 	}
-	for k, v := range values {
-		result[k] = v
+	job, err := s.resolve(ctx, meta, templatePath, req.Values, req.Profile, req.Stdin)
+	if err != nil {
+		return nil, err
 	}
-	return result
+	job.opts.Runtime = runtimeFor(job.meta, fillRuntime(req.Now, req.Seed))
+	return job, nil
 }
 
-func validateVariables(meta *models.TemplateMetadata, values map[string]string) error {
-	for _, variable := range meta.Variables {
-		if !variable.Required {
-			continue
-		}
-		if values[variable.Key] == "" {
-			return pkgtemplate.ErrMissingVariable{Key: variable.Key}
-		}
+// resolve aplica a herança, mescla valores, resolve segredos, valida e compila
+// as opções de renderização para um template já carregado.
+func (s *Service) resolve(ctx context.Context, meta *models.TemplateMetadata, templatePath string, input map[string]any, profile string, stdin io.Reader) (*renderJob, error) {
+	layered, err := s.inherit(ctx, meta, templatePath)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(meta.Name, "load").Inc()
+		return nil, err
+	}
+	meta = layered.meta
+
+	layers, err := templateLayers(meta, profile)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(meta.Name, "validation").Inc()
+		return nil, err
+	}
+	values, _ := valuespkg.Resolve(append(layers, valuespkg.Layer{Source: "input", Values: input}))
+	secrets, err := resolveSecrets(meta, values, stdin)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(meta.Name, "validation").Inc()
+		return nil, err
+	}
+	// As entradas são validadas antes das variáveis computadas, que dependem
+	// delas: uma entrada ausente gera ValidationError, não erro de template.
+	if err := validateVariables(meta, values, false); err != nil {
+		s.metrics.errors.WithLabelValues(meta.Name, "validation").Inc()
+		return nil, secrets.redactError(err)
+	}
+	if _, err := computeValues(meta, values); err != nil {
+		s.metrics.errors.WithLabelValues(meta.Name, "validation").Inc()
+		return nil, secrets.redactError(err)
+	}
+	if err := validateVariables(meta, values, true); err != nil {
+		s.metrics.errors.WithLabelValues(meta.Name, "validation").Inc()
+		return nil, secrets.redactError(err)
+	}
+
+	rules, err := pathRules(meta)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(meta.Name, "validation").Inc()
+		return nil, err
 	}
-	return nil
+	delimiters, err := delimiterRules(meta)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(meta.Name, "validation").Inc()
+		return nil, err
+	}
+
+	sourceModules, transforms, err := moduleTransforms(layered.layers, values)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(meta.Name, "load").Inc()
+		return nil, err
+	}
+	for _, sourceModule := range sourceModules {
+		s.logger.Debug().
+			Str("from", sourceModule).
+			Str("to", stringValue(values, moduleVariable)).
+			Msg("reescrevendo caminho do módulo Go")
+	}
+
+	return &renderJob{
+		meta:         meta,
+		templatePath: templatePath,
+		layers:       layered.layers,
+		profile:      profile,
+		values:       values,
+		secrets:      secrets,
+		opts: pkgtemplate.RenderOptions{
+			Ignore:      layered.ignorePatterns(),
+			Rules:       rules,
+			Transforms:  transforms,
+			Bases:       layered.bases(),
+			Partials:    layered.partials,
+			Delimiters:  delimiters,
+			CopyOnly:    meta.CopyOnly,
+			RenderOnly:  meta.RenderOnly,
+			Concurrency: s.cfg.Concurrency,
+		},
+	}, nil
 }
 
-func (s *Service) prepareOutput(path string, overwrite bool) error {
+// fillRuntime completa um estado de runtime parcial com o relógio e uma semente
+// aleatória.
+func fillRuntime(now time.Time, seed int64) models.RuntimeState {
+	if now.IsZero() {
+		now = time.Now().UTC().Truncate(time.Second)
+	}
+	for seed == 0 {
+		seed = rand.Int63() //nolint:gosec // semente de reprodutibilidade, não segredo
+	}
+	return models.RuntimeState{Now: now, Seed: seed}
+}
+
+// runtimeFor habilita now e uuid quando o template declara runtime_funcs.
+func runtimeFor(meta *models.TemplateMetadata, state models.RuntimeState) *pkgtemplate.Runtime {
+	if !meta.RuntimeFuncs {
+		return nil
+	}
+	return &pkgtemplate.Runtime{Now: state.Now, Seed: state.Seed}
+}
+
+// stringValue retorna o valor escalar em path como texto.
+func stringValue(tree map[string]any, path string) string {
+	value, _ := valuespkg.Lookup(tree, path)
+	return valuespkg.String(value)
+}
+
+// checkOutput valida o diretório de saída sem alterá-lo e informa se ele já existe.
+func checkOutput(path string, overwrite bool) (bool, error) {
 	info, err := os.Stat(path)
 	if err == nil {
 		if !info.IsDir() {
-			return fmt.Errorf("output path is not a directory: %s", path)
+			return true, fmt.Errorf("output path is not a directory: %s", path)
 		}
 
 		entries, err := os.ReadDir(path)
 		if err != nil {
-			return fmt.Errorf("read output dir: %w", err)
+			return true, fmt.Errorf("read output dir: %w", err)
 		}
 		if len(entries) > 0 && !overwrite {
-			return fmt.Errorf("output directory is not empty: %s", path)
+			return true, fmt.Errorf("output directory is not empty: %s", path)
 		}
-		if overwrite {
-			for _, entry := range entries {
-				if err := os.RemoveAll(filepath.Join(path, entry.Name())); err != nil {
-					return fmt.Errorf("clear output dir: %w", err)
-				}
-			}
-		}
-		return nil
+		return true, nil
 	}
 	if os.IsNotExist(err) {
-		return os.MkdirAll(path, 0o755)
+		return false, nil
 	}
-	return fmt.Errorf("stat output dir: %w", err)
+	return false, fmt.Errorf("stat output dir: %w", err)
 }
-
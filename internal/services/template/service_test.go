@@ -30,7 +30,7 @@ func TestServiceRenderSuccess(t *testing.T) {
 	meta := &models.TemplateMetadata{
 		Name:      "demo",
 		Variables: []models.TemplateVariable{{Key: "service_name", Required: true}},
-		Defaults:  map[string]string{"service_name": "fallback"},
+		Defaults:  map[string]any{"service_name": "fallback"},
 	}
 
 	templateDir := t.TempDir()
@@ -58,7 +58,7 @@ const Service = "{{ toUpper .service_name }}"
 	resp, err := service.Render(context.Background(), RenderRequest{
 		TemplateName: "demo",
 		OutputDir:    outputDir,
-		Values: map[string]string{
+		Values: map[string]any{
 			"service_name": "ultra",
 		},
 		Overwrite: true,
@@ -105,7 +105,7 @@ func TestServiceRenderMissingVariable(t *testing.T) {
 	_, err := service.Render(context.Background(), RenderRequest{
 		TemplateName: "demo",
 		OutputDir:    t.TempDir(),
-		Values:       map[string]string{},
+		Values:       map[string]any{},
 		Overwrite:    true,
 	})
 
@@ -134,10 +134,10 @@ func TestServiceList(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	service := New(cfg, logger, reg, mockRepo)
 
-	templates, err := service.List(context.Background())
+	result, err := service.List(context.Background(), ListOptions{})
 	require.NoError(t, err)
-	require.Len(t, templates, 1)
-	require.Equal(t, "demo", templates[0].Name)
+	require.Len(t, result.Templates, 1)
+	require.Equal(t, "demo", result.Templates[0].Name)
 }
 
 func TestServiceRenderOutputNotEmpty(t *testing.T) {
@@ -171,7 +171,7 @@ func TestServiceRenderOutputNotEmpty(t *testing.T) {
 	_, err := service.Render(context.Background(), RenderRequest{
 		TemplateName: "demo",
 		OutputDir:    outputDir,
-		Values:       map[string]string{},
+		Values:       map[string]any{},
 		Overwrite:    false,
 	})
 
@@ -317,7 +317,123 @@ func TestServiceRenderOutputIsFile(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestServiceRenderRewritesGoModule(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	meta := &models.TemplateMetadata{
+		Name:      "demo",
+		Variables: []models.TemplateVariable{{Key: "module_name", Type: models.VariableTypeGoModule}},
+		Defaults:  map[string]any{"module_name": "github.com/example/demo"},
+	}
+
+	templateDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "go.mod"), []byte("module github.com/vertikon/seed\n\ngo 1.24.0\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(templateDir, "cmd"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "cmd", "main.go"), []byte(`package main
+
+import "github.com/vertikon/seed/internal/app"
+
+func main() { app.Run() }
+`), 0o644))
+
+	mockRepo.EXPECT().
+		LoadTemplate(gomock.Any(), "demo").
+		Return(meta, templateDir, nil).
+		Times(1)
+
+	cfg := config.RenderingConfig{
+		OperationTimeout: 5 * time.Second,
+		MaxRetryAttempts: 1,
+	}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	outputDir := t.TempDir()
+	_, err := service.Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    outputDir,
+		Values:       map[string]any{"module_name": "github.com/acme/orders"},
+		Overwrite:    true,
+	})
+	require.NoError(t, err)
+
+	gomod, err := os.ReadFile(filepath.Join(outputDir, "go.mod"))
+	require.NoError(t, err)
+	require.Contains(t, string(gomod), "module github.com/acme/orders\n")
+
+	mainGo, err := os.ReadFile(filepath.Join(outputDir, "cmd", "main.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(mainGo), `import "github.com/acme/orders/internal/app"`)
+}
+
+func TestModuleTransformsPrefixedBase(t *testing.T) {
+	t.Parallel()
+
+	base, derived := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(base, "go.mod"), []byte("module github.com/acme\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(derived, "go.mod"), []byte("module github.com/vertikon/derived\n"), 0o644))
+
+	sources, transforms, err := moduleTransforms([]string{base, derived}, map[string]any{"module_name": "github.com/acme/orders"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"github.com/vertikon/derived", "github.com/acme"}, sources)
+	require.Len(t, transforms, 1)
+
+	out, err := transforms[0]("main.go", []byte("package main\n\nimport (\n\t\"github.com/acme/lib\"\n\t\"github.com/vertikon/derived/api\"\n)\n"))
+	require.NoError(t, err)
+	require.Equal(t, "package main\n\nimport (\n\t\"github.com/acme/orders/lib\"\n\t\"github.com/acme/orders/api\"\n)\n", string(out))
+}
+
 func testLogger() zerolog.Logger {
 	var buf bytes.Buffer
 	return zerolog.New(&buf).With().Timestamp().Logger()
 }
+
+func TestServiceRenderNestedValues(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+
+	meta := &models.TemplateMetadata{
+		Name:      "demo",
+		Variables: []models.TemplateVariable{{Key: "services", Type: models.VariableTypeList, Required: true}},
+		Defaults: map[string]any{
+			"database": map[string]any{"host": "localhost", "port": 5432},
+		},
+	}
+
+	templateDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "services.txt.tmpl"), []byte(
+		"{{ range .services }}{{ .name }}:{{ .port }}\n{{ end }}db={{ .database.host }}:{{ .database.port }}\n"), 0o644))
+
+	mockRepo.EXPECT().
+		LoadTemplate(gomock.Any(), "demo").
+		Return(meta, templateDir, nil).
+		Times(1)
+
+	service := New(config.RenderingConfig{OperationTimeout: 5 * time.Second}, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	outputDir := t.TempDir()
+	_, err := service.Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    outputDir,
+		Values: map[string]any{
+			"services": []any{
+				map[string]any{"name": "users", "port": 8080},
+				map[string]any{"name": "orders", "port": 8081},
+			},
+			"database": map[string]any{"host": "db"},
+		},
+		Overwrite: true,
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "services.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "users:8080\norders:8081\ndb=db:5432\n", string(data))
+}
@@ -0,0 +1,62 @@
+package template
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/repository/archive"
+	"github.com/vertikon/mcp-ultra-templates/internal/repository/git"
+)
+
+// subdirParam é o parâmetro de query que aponta o diretório de templates dentro
+// de um repositório git ou arquivo compactado.
+const subdirParam = "subdir"
+
+// NewRepositoryFromURI escolhe a implementação de Repository conforme templates_path:
+//
+//	git+file:///srv/templates.git#v1.2.0       repositório git na ref informada
+//	git+https://host/org/templates.git?subdir=templates
+//	https://host/templates.tar.gz, ./templates.zip   arquivo .tar.gz, .tgz ou .zip
+//	./templates                                 diretório local
+//
+// Fontes remotas são armazenadas em cacheDir.
+func NewRepositoryFromURI(uri, cacheDir string) (Repository, error) {
+	if rest, ok := strings.CutPrefix(uri, "git+"); ok {
+		u, err := url.Parse(rest)
+		if err != nil {
+			return nil, fmt.Errorf("parse templates uri: %w", err)
+		}
+		switch u.Scheme {
+		case "file", "http", "https", "ssh":
+		default:
+			return nil, fmt.Errorf("unsupported git transport in templates uri: %s", u.Scheme)
+		}
+		ref := u.Fragment
+		subdir := popSubdir(u)
+		u.Fragment = ""
+		return git.New(u.String(), ref, subdir, cacheDir), nil
+	}
+
+	if archive.IsArchive(uri) {
+		source, subdir := uri, ""
+		if u, err := url.Parse(uri); err == nil && (u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "file") {
+			subdir = popSubdir(u)
+			source = u.String()
+		}
+		return archive.New(source, subdir, cacheDir), nil
+	}
+
+	return NewDefaultRepository(uri), nil
+}
+
+// popSubdir remove o parâmetro subdir da URL, preservando os demais.
+func popSubdir(u *url.URL) string {
+	query := u.Query()
+	subdir := query.Get(subdirParam)
+	if subdir != "" {
+		query.Del(subdirParam)
+		u.RawQuery = query.Encode()
+	}
+	return subdir
+}
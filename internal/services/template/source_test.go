@@ -0,0 +1,32 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/repository/archive"
+	"github.com/vertikon/mcp-ultra-templates/internal/repository/fs"
+	"github.com/vertikon/mcp-ultra-templates/internal/repository/git"
+)
+
+func TestNewRepositoryFromURI(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	cases := map[string]any{
+		"templates":                                      &fs.Repository{},
+		"git+file:///srv/templates.git#v1.2.0":           &git.Repository{},
+		"git+https://example.com/t.git?subdir=templates": &git.Repository{},
+		"https://example.com/templates.tar.gz":           &archive.Repository{},
+		"./templates.zip":                                &archive.Repository{},
+	}
+	for uri, expected := range cases {
+		repo, err := NewRepositoryFromURI(uri, cacheDir)
+		require.NoError(t, err, uri)
+		require.IsType(t, expected, repo, uri)
+	}
+
+	_, err := NewRepositoryFromURI("git+ftp://example.com/t.git", cacheDir)
+	require.ErrorContains(t, err, "unsupported git transport")
+}
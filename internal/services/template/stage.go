@@ -0,0 +1,160 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// Prefixos dos diretórios temporários criados ao lado da saída, no mesmo
+// sistema de arquivos, para que a promoção use apenas renames.
+const (
+	stagingPattern = ".%s.mcp-staging-*"
+	backupPattern  = ".%s.mcp-backup-*"
+)
+
+// vcsDirs são os metadados de controle de versão da saída. Eles nunca são
+// mesclados nem substituídos pelo conteúdo do staging, para não perder o
+// histórico do projeto.
+var vcsDirs = map[string]bool{".git": true, ".hg": true, ".svn": true}
+
+// transientErrnos são falhas de I/O que podem desaparecer em nova tentativa.
+var transientErrnos = []error{
+	syscall.EAGAIN,
+	syscall.EBUSY,
+	syscall.EINTR,
+	syscall.EMFILE,
+	syscall.ENFILE,
+	syscall.ETIMEDOUT,
+	syscall.ETXTBSY,
+}
+
+// retryable informa se err é uma falha transitória de I/O. Erros de template,
+// de validação e cancelamentos são determinísticos e não são repetidos.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	for _, errno := range transientErrnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// stage é o diretório onde o projeto é gerado, com hooks e lockfile, antes de
+// substituir a saída. Até promote, a saída não é alterada.
+type stage struct {
+	dir    string
+	output string
+	exists bool
+	// remove lista caminhos relativos da saída que merge remove junto com a
+	// promoção, com o mesmo rollback.
+	remove []string
+}
+
+// newStage cria o diretório de staging ao lado de output. exists indica se a
+// saída já existe, conforme checkOutput.
+func newStage(output string, exists bool) (*stage, error) {
+	output, err := filepath.Abs(output)
+	if err != nil {
+		return nil, fmt.Errorf("resolve output dir: %w", err)
+	}
+	if exists {
+		if output, err = filepath.EvalSymlinks(output); err != nil {
+			return nil, fmt.Errorf("resolve output dir: %w", err)
+		}
+	}
+
+	parent := filepath.Dir(output)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return nil, fmt.Errorf("create output parent: %w", err)
+	}
+	dir, err := os.MkdirTemp(parent, fmt.Sprintf(stagingPattern, filepath.Base(output)))
+	if err != nil {
+		return nil, fmt.Errorf("create staging dir: %w", err)
+	}
+	if err := os.Chmod(dir, 0o755); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("create staging dir: %w", err)
+	}
+	return &stage{dir: dir, output: output, exists: exists}, nil
+}
+
+// promote coloca o conteúdo do staging na saída. Uma saída nova é criada com um
+// único rename. Em uma saída existente, cujo diretório é mantido, as entradas
+// atuais vão para um backup antes que as novas entrem; se alguma etapa falhar,
+// o estado anterior é restaurado. Os diretórios de vcsDirs da saída são
+// mantidos, e os do staging só entram quando a saída não tem o seu. Retorna o
+// backup, que o chamador remove depois da promoção.
+func (s *stage) promote() (string, error) {
+	if !s.exists {
+		if err := os.Rename(s.dir, s.output); err != nil {
+			return "", fmt.Errorf("promote output: %w", err)
+		}
+		return "", nil
+	}
+
+	backup, err := os.MkdirTemp(filepath.Dir(s.output), fmt.Sprintf(backupPattern, filepath.Base(s.output)))
+	if err != nil {
+		return "", fmt.Errorf("create backup dir: %w", err)
+	}
+
+	var moved, placed []string
+	rollback := func(cause error) error {
+		var errs []error
+		for _, name := range placed {
+			errs = append(errs, os.RemoveAll(filepath.Join(s.output, name)))
+		}
+		for _, name := range moved {
+			errs = append(errs, os.Rename(filepath.Join(backup, name), filepath.Join(s.output, name)))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return fmt.Errorf("promote output: %w (rollback failed, previous contents kept in %s: %v)", cause, backup, err)
+		}
+		_ = os.RemoveAll(backup)
+		return fmt.Errorf("promote output: %w", cause)
+	}
+
+	previous, err := os.ReadDir(s.output)
+	if err != nil {
+		return "", rollback(err)
+	}
+	for _, entry := range previous {
+		if vcsDirs[entry.Name()] {
+			continue
+		}
+		if err := os.Rename(filepath.Join(s.output, entry.Name()), filepath.Join(backup, entry.Name())); err != nil {
+			return "", rollback(err)
+		}
+		moved = append(moved, entry.Name())
+	}
+
+	staged, err := os.ReadDir(s.dir)
+	if err != nil {
+		return "", rollback(err)
+	}
+	for _, entry := range staged {
+		if vcsDirs[entry.Name()] {
+			if _, err := os.Lstat(filepath.Join(s.output, entry.Name())); err == nil {
+				continue
+			}
+		}
+		if err := os.Rename(filepath.Join(s.dir, entry.Name()), filepath.Join(s.output, entry.Name())); err != nil {
+			return "", rollback(err)
+		}
+		placed = append(placed, entry.Name())
+	}
+	return backup, nil
+}
+
+// discard remove o staging; após promote, remove apenas o diretório vazio que
+// restou.
+func (s *stage) discard() error {
+	return os.RemoveAll(s.dir)
+}
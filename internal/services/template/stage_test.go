@@ -0,0 +1,125 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+func TestRetryable(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, retryable(fmt.Errorf("write rendered file: %w", &os.PathError{Op: "open", Path: "x", Err: syscall.EMFILE})))
+	require.True(t, retryable(&os.PathError{Op: "write", Path: "x", Err: os.ErrDeadlineExceeded}))
+	require.False(t, retryable(errors.New(`template: main.go.tmpl:1: function "nope" not defined`)))
+	require.False(t, retryable(&os.PathError{Op: "open", Path: "x", Err: syscall.EACCES}))
+	require.False(t, retryable(fmt.Errorf("render: %w", context.DeadlineExceeded)))
+}
+
+func TestStagePromote(t *testing.T) {
+	t.Parallel()
+
+	parent := t.TempDir()
+	output := filepath.Join(parent, "out")
+
+	created, err := newStage(output, false)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(created.dir, "new.txt"), []byte("v1"), 0o644))
+	backup, err := created.promote()
+	require.NoError(t, err)
+	require.Empty(t, backup)
+	require.NoError(t, created.discard())
+	require.FileExists(t, filepath.Join(output, "new.txt"))
+
+	replaced, err := newStage(output, true)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(replaced.dir, "other.txt"), []byte("v2"), 0o644))
+	backup, err = replaced.promote()
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(backup, "new.txt"))
+	require.NoError(t, os.RemoveAll(backup))
+	require.NoError(t, replaced.discard())
+
+	entries, err := os.ReadDir(output)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "other.txt", entries[0].Name())
+
+	entries, err = os.ReadDir(parent)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "staging e backup removidos")
+}
+
+func TestStagePromoteRollback(t *testing.T) {
+	t.Parallel()
+
+	parent := t.TempDir()
+	output := filepath.Join(parent, "out")
+	require.NoError(t, os.MkdirAll(output, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(output, "keep.txt"), []byte("old"), 0o644))
+
+	broken, err := newStage(output, true)
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(broken.dir))
+
+	_, err = broken.promote()
+	require.ErrorContains(t, err, "promote output")
+
+	data, err := os.ReadFile(filepath.Join(output, "keep.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "old", string(data))
+	entries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "backup removido após restaurar a saída")
+}
+
+func TestServiceRenderFailureKeepsOutput(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	templateDir := writeTemplateFiles(t, map[string]string{
+		"a.txt":      "ok\n",
+		"b.txt.tmpl": "{{ .missing }}\n",
+	})
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(&models.TemplateMetadata{Name: "demo"}, templateDir, nil)
+
+	var logs bytes.Buffer
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 3}
+	service := New(cfg, zerolog.New(&logs), prometheus.NewRegistry(), mockRepo)
+
+	parent := t.TempDir()
+	outputDir := filepath.Join(parent, "project")
+	require.NoError(t, os.MkdirAll(outputDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "keep.txt"), []byte("mine\n"), 0o644))
+
+	_, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: outputDir, Overwrite: true})
+	require.ErrorContains(t, err, `map has no entry for key "missing"`)
+	require.NotContains(t, logs.String(), "tentando novamente", "erros de template não são repetidos")
+
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "keep.txt", entries[0].Name())
+
+	entries, err = os.ReadDir(parent)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "staging removido")
+}
@@ -0,0 +1,412 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/pkg/diff"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// RejectSuffix é anexado ao caminho quando a versão nova de um arquivo não pode
+// ser mesclada no lugar (binários ou arquivos removidos localmente).
+const RejectSuffix = ".rej"
+
+// UpgradeAction descreve o que a atualização fez com um arquivo.
+type UpgradeAction string
+
+// Ações possíveis em uma atualização.
+const (
+	UpgradeAdded     UpgradeAction = "added"
+	UpgradeUpdated   UpgradeAction = "updated"
+	UpgradeMerged    UpgradeAction = "merged"
+	UpgradeRemoved   UpgradeAction = "removed"
+	UpgradeKept      UpgradeAction = "kept"
+	UpgradeConflict  UpgradeAction = "conflict"
+	UpgradeUnchanged UpgradeAction = "unchanged"
+)
+
+// UpgradeRequest contém os parâmetros da atualização de um projeto gerado.
+type UpgradeRequest struct {
+	OutputDir string
+	// ToVersion é a versão alvo, exata ou uma restrição semver como "^1.2"; vazio
+	// usa a versão atual do repositório.
+	ToVersion string
+	// Values são mesclados sobre os valores registrados no lockfile.
+	Values map[string]any
+	// AllowHooks autoriza hooks com action command na versão nova.
+	AllowHooks bool
+}
+
+// UpgradedFile é o resultado da atualização de um arquivo.
+type UpgradedFile struct {
+	Path   string        `json:"path"`
+	Action UpgradeAction `json:"action"`
+	Reason string        `json:"reason,omitempty"`
+}
+
+// UpgradeResult resume a atualização.
+type UpgradeResult struct {
+	Template    string                `json:"template"`
+	FromVersion string                `json:"from_version"`
+	ToVersion   string                `json:"to_version"`
+	Output      string                `json:"output"`
+	Files       []UpgradedFile        `json:"files"`
+	Summary     map[UpgradeAction]int `json:"summary"`
+}
+
+// Conflicts retorna quantos arquivos exigem resolução manual.
+func (r *UpgradeResult) Conflicts() int {
+	return r.Summary[UpgradeConflict]
+}
+
+// Upgrade renderiza novamente o template registrado no lockfile em uma nova versão
+// e faz merge de três vias com os arquivos do projeto: a base é a versão anterior
+// renderizada com os mesmos valores, "ours" é o disco e "theirs" a versão nova
+// após os hooks de post_render. Arquivos cujo hash ainda é o do lockfile não
+// foram editados e recebem a versão nova. Os arquivos alterados são gerados em
+// staging e promovidos juntos, com rollback, como em Render; até lá o projeto
+// não é alterado.
+func (s *Service) Upgrade(ctx context.Context, req UpgradeRequest) (*UpgradeResult, error) {
+	if req.OutputDir == "" {
+		return nil, errors.New("output directory is required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.OperationTimeout)
+	defer cancel()
+
+	lock, err := ReadLockfile(req.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	values := valuespkg.Merge(valuespkg.Merge(nil, lock.Values), req.Values)
+	var masked []string
+	valuespkg.Walk(values, func(path string, value any) {
+		if value == MaskedValue {
+			masked = append(masked, path)
+		}
+	})
+	if len(masked) > 0 {
+		return nil, fmt.Errorf("value for %s is masked in the lockfile and must be provided again", strings.Join(masked, ", "))
+	}
+
+	baseMeta, basePath, err := s.repo.LoadTemplateVersion(ctx, lock.Template, lock.Version)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(lock.Template, "load").Inc()
+		return nil, fmt.Errorf("load recorded template version: %w", err)
+	}
+
+	newMeta, newPath, err := s.loadVersion(ctx, lock.Template, req.ToVersion)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(lock.Template, "load").Inc()
+		return nil, err
+	}
+
+	baseJob, err := s.resolve(ctx, baseMeta, basePath, values, lock.Profile, nil)
+	if err != nil {
+		return nil, err
+	}
+	if hash, err := hashDirectory(baseJob.layers...); err == nil && hash != lock.SourceHash {
+		s.logger.Warn().
+			Str("template", lock.Template).
+			Str("version", lock.Version).
+			Msg("conteúdo da versão registrada difere do lockfile; o merge pode gerar conflitos extras")
+	}
+	newJob, err := s.resolve(ctx, newMeta, newPath, values, lock.Profile, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateHooks(newJob.meta.Hooks, req.AllowHooks); err != nil {
+		s.metrics.errors.WithLabelValues(lock.Template, "validation").Inc()
+		return nil, err
+	}
+	var state models.RuntimeState
+	if lock.Runtime != nil {
+		state = *lock.Runtime
+	}
+	state = fillRuntime(state.Now, state.Seed)
+	baseJob.opts.Runtime = runtimeFor(baseJob.meta, state)
+	newJob.opts.Runtime = runtimeFor(newJob.meta, state)
+
+	baseTree, err := pkgtemplate.BuildTree(ctx, baseJob.templatePath, baseJob.values, baseJob.opts)
+	if err != nil {
+		return nil, baseJob.secrets.redactError(fmt.Errorf("render recorded version: %w", err))
+	}
+	newTree, err := pkgtemplate.BuildTree(ctx, newJob.templatePath, newJob.values, newJob.opts)
+	if err != nil {
+		return nil, newJob.secrets.redactError(fmt.Errorf("render target version: %w", err))
+	}
+
+	result := &UpgradeResult{
+		Template:    lock.Template,
+		FromVersion: lock.Version,
+		ToVersion:   newJob.meta.Version,
+		Output:      req.OutputDir,
+		Summary:     map[UpgradeAction]int{},
+	}
+
+	staging, err := newStage(req.OutputDir, true)
+	if err != nil {
+		s.metrics.errors.WithLabelValues(lock.Template, "output").Inc()
+		return nil, err
+	}
+	defer func() {
+		if err := staging.discard(); err != nil {
+			s.logger.Warn().Err(err).Str("dir", staging.dir).Msg("não foi possível remover o diretório de staging")
+		}
+	}()
+
+	// A versão nova é gerada inteira no staging e passa pelos hooks, como em
+	// Render; depois cada arquivo é comparado com o projeto e só o que deve
+	// mudar continua no staging.
+	if err := newTree.Write(ctx, staging.dir); err != nil {
+		s.metrics.errors.WithLabelValues(lock.Template, "render").Inc()
+		return nil, newJob.secrets.redactError(fmt.Errorf("render target version: %w", err))
+	}
+	hooks := upgradeHooks(newJob.meta.Hooks.PostRender)
+	if err := s.runHooks(ctx, PhasePostRender, hooks, staging, newJob.values, newJob.secrets); err != nil {
+		s.metrics.errors.WithLabelValues(lock.Template, "hook").Inc()
+		return nil, err
+	}
+
+	// Os hashes registram a versão do template como ficou após os hooks, e não
+	// o conteúdo mesclado, para que edições locais continuem detectáveis na
+	// próxima atualização.
+	newLock, err := newLockfile(newJob, newTree)
+	if err == nil && len(hooks) > 0 {
+		err = rehashFiles(newLock, staging.dir, newJob.secrets)
+	}
+	if err != nil {
+		s.metrics.errors.WithLabelValues(lock.Template, "lockfile").Inc()
+		return nil, err
+	}
+
+	baseFiles := make(map[string][]byte, len(baseTree.Files))
+	for i := range baseTree.Files {
+		content, err := baseTree.Files[i].Bytes()
+		if err != nil {
+			return nil, err
+		}
+		baseFiles[baseTree.Files[i].Path] = content
+	}
+
+	theirsLabel := fmt.Sprintf("template %s", newJob.meta.Version)
+	for _, file := range newTree.Files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		theirs, err := os.ReadFile(filepath.Join(staging.dir, filepath.FromSlash(file.Path)))
+		if errors.Is(err, os.ErrNotExist) {
+			// Removido por um hook: tratado como removido do template.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read staged file: %w", err)
+		}
+		rendered, err := file.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		base, inBase := baseFiles[file.Path]
+		delete(baseFiles, file.Path)
+
+		entry, err := upgradeFile(req.OutputDir, staging.dir, upgradeInput{
+			path:     file.Path,
+			theirs:   theirs,
+			base:     base,
+			inBase:   inBase,
+			changed:  !inBase || !bytes.Equal(base, rendered),
+			recorded: lock.Files[file.Path],
+		}, theirsLabel)
+		if err != nil {
+			s.metrics.errors.WithLabelValues(lock.Template, "upgrade").Inc()
+			return nil, err
+		}
+		result.Files = append(result.Files, entry)
+	}
+
+	for path, base := range baseFiles {
+		entry, err := removeUpstream(req.OutputDir, path, base, lock.Files[path])
+		if err != nil {
+			s.metrics.errors.WithLabelValues(lock.Template, "upgrade").Inc()
+			return nil, err
+		}
+		if entry.Action == UpgradeRemoved {
+			staging.remove = append(staging.remove, path)
+		}
+		if entry.Action != "" {
+			result.Files = append(result.Files, entry)
+		}
+	}
+
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].Path < result.Files[j].Path })
+	for _, file := range result.Files {
+		result.Summary[file.Action]++
+	}
+
+	if err := writeLockfile(staging.dir, newLock); err != nil {
+		s.metrics.errors.WithLabelValues(lock.Template, "lockfile").Inc()
+		return nil, err
+	}
+
+	sort.Strings(staging.remove)
+	_, backup, err := staging.merge(&conflictPolicy{mode: ConflictOverwrite})
+	if err != nil {
+		s.metrics.errors.WithLabelValues(lock.Template, "output").Inc()
+		return nil, err
+	}
+	if err := os.RemoveAll(backup); err != nil {
+		s.logger.Warn().Err(err).Str("dir", backup).Msg("não foi possível remover o backup da saída anterior")
+	}
+
+	s.logger.Info().
+		Str("template", lock.Template).
+		Str("from", result.FromVersion).
+		Str("to", result.ToVersion).
+		Int("conflicts", result.Conflicts()).
+		Msg("projeto atualizado")
+
+	return result, nil
+}
+
+// upgradeHooks retorna os passos de post_render executados na atualização:
+// git_init só faz sentido na geração do projeto.
+func upgradeHooks(steps []models.HookStep) []models.HookStep {
+	var hooks []models.HookStep
+	for _, step := range steps {
+		if step.Action != models.HookGitInit {
+			hooks = append(hooks, step)
+		}
+	}
+	return hooks
+}
+
+// upgradeInput descreve um arquivo da versão nova.
+type upgradeInput struct {
+	path string
+	// theirs é o conteúdo gerado no staging, após os hooks.
+	theirs []byte
+	base   []byte
+	inBase bool
+	// changed indica que o template alterou o arquivo desde a versão registrada.
+	changed bool
+	// recorded é o hash registrado no lockfile para o arquivo.
+	recorded string
+}
+
+// upgradeFile compara a versão nova de um arquivo com o projeto em root e deixa
+// em staging apenas o que precisa mudar.
+func upgradeFile(root, staging string, in upgradeInput, theirsLabel string) (UpgradedFile, error) {
+	entry := UpgradedFile{Path: in.path}
+	target := filepath.Join(staging, filepath.FromSlash(in.path))
+
+	ours, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(in.path)))
+	if errors.Is(err, os.ErrNotExist) {
+		switch {
+		case !in.inBase:
+			entry.Action = UpgradeAdded
+			return entry, nil
+		case !in.changed:
+			entry.Action = UpgradeKept
+			entry.Reason = "removido localmente"
+			return entry, dropStaged(staging, in.path)
+		default:
+			entry.Action = UpgradeConflict
+			entry.Reason = "removido localmente e alterado no template; veja " + in.path + RejectSuffix
+			return entry, rejectStaged(target)
+		}
+	}
+	if err != nil {
+		return entry, fmt.Errorf("read project file: %w", err)
+	}
+
+	switch {
+	case bytes.Equal(ours, in.theirs):
+		entry.Action = UpgradeUnchanged
+		return entry, dropStaged(staging, in.path)
+	case pristine(ours, in.recorded) || in.inBase && bytes.Equal(ours, in.base):
+		entry.Action = UpgradeUpdated
+		return entry, nil
+	case !in.changed:
+		entry.Action = UpgradeKept
+		return entry, dropStaged(staging, in.path)
+	case diff.IsBinary(ours) || diff.IsBinary(in.theirs):
+		entry.Action = UpgradeConflict
+		entry.Reason = "arquivo binário alterado nos dois lados; veja " + in.path + RejectSuffix
+		return entry, rejectStaged(target)
+	}
+
+	merged, conflict := diff.Merge(in.base, ours, in.theirs, "local", theirsLabel)
+	entry.Action = UpgradeMerged
+	if conflict {
+		entry.Action = UpgradeConflict
+		entry.Reason = "marcadores de conflito no arquivo"
+	}
+	return entry, writeUpgraded(target, merged)
+}
+
+// removeUpstream trata arquivos que existiam na versão anterior e saíram do template.
+// Retorna uma entrada vazia quando o arquivo também já não existe no projeto; a
+// remoção de fato é feita na promoção do staging.
+func removeUpstream(root, path string, base []byte, recorded string) (UpgradedFile, error) {
+	target := filepath.Join(root, filepath.FromSlash(path))
+	ours, err := os.ReadFile(target)
+	if errors.Is(err, os.ErrNotExist) {
+		return UpgradedFile{}, nil
+	}
+	if err != nil {
+		return UpgradedFile{}, fmt.Errorf("read project file: %w", err)
+	}
+
+	if !pristine(ours, recorded) && !bytes.Equal(ours, base) {
+		return UpgradedFile{Path: path, Action: UpgradeConflict, Reason: "removido no template e alterado localmente; arquivo mantido"}, nil
+	}
+	return UpgradedFile{Path: path, Action: UpgradeRemoved}, nil
+}
+
+// pristine informa se ours ainda tem o hash registrado no lockfile. Hashes
+// mascarados nunca coincidem.
+func pristine(ours []byte, recorded string) bool {
+	return recorded != "" && recorded != MaskedValue && hashBytes(ours) == recorded
+}
+
+// dropStaged remove do staging um arquivo que não deve ser promovido, junto com
+// os diretórios que ficaram vazios.
+func dropStaged(staging, path string) error {
+	target := filepath.Join(staging, filepath.FromSlash(path))
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("drop staged file: %w", err)
+	}
+	for dir := filepath.Dir(target); dir != staging; dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// rejectStaged renomeia a versão nova no staging para o arquivo RejectSuffix.
+func rejectStaged(target string) error {
+	if err := os.Rename(target, target+RejectSuffix); err != nil {
+		return fmt.Errorf("write rejected file: %w", err)
+	}
+	return nil
+}
+
+// writeUpgraded grava o resultado do merge sobre a versão nova no staging,
+// preservando o modo do arquivo.
+func writeUpgraded(target string, content []byte) error {
+	if err := os.WriteFile(target, content, 0o644); err != nil {
+		return fmt.Errorf("write upgraded file: %w", err)
+	}
+	return nil
+}
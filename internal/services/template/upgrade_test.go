@@ -0,0 +1,176 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+func writeTemplateFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestServiceUpgrade(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	v1 := &models.TemplateMetadata{Name: "demo", Version: "1.0.0"}
+	v2 := &models.TemplateMetadata{Name: "demo", Version: "2.0.0"}
+
+	v1Dir := writeTemplateFiles(t, map[string]string{
+		"edited.txt.tmpl": "{{ .name }}\ntwo\nthree\n",
+		"local.txt":       "base\n",
+		"conflict.txt":    "x\n",
+		"dropped.txt":     "old\n",
+		"gone.txt":        "same\n",
+	})
+	v2Dir := writeTemplateFiles(t, map[string]string{
+		"edited.txt.tmpl": "{{ .name }}\ntwo\nthree\nfour\n",
+		"local.txt":       "base\n",
+		"conflict.txt":    "y\n",
+		"added.txt":       "new\n",
+		"gone.txt":        "changed\n",
+	})
+
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(v1, v1Dir, nil).Times(1)
+	mockRepo.EXPECT().LoadTemplateVersion(gomock.Any(), "demo", "1.0.0").Return(v1, v1Dir, nil).Times(1)
+	mockRepo.EXPECT().LoadTemplateVersion(gomock.Any(), "demo", "2.0.0").Return(v2, v2Dir, nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	outputDir := t.TempDir()
+	_, err := service.Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    outputDir,
+		Values:       map[string]any{"name": "one"},
+	})
+	require.NoError(t, err)
+
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(outputDir, name), []byte(content), 0o644))
+	}
+	write("edited.txt", "ONE\ntwo\nthree\n")
+	write("local.txt", "mine\n")
+	write("conflict.txt", "z\n")
+	require.NoError(t, os.Remove(filepath.Join(outputDir, "gone.txt")))
+
+	result, err := service.Upgrade(context.Background(), UpgradeRequest{OutputDir: outputDir, ToVersion: "2.0.0"})
+	require.NoError(t, err)
+
+	actions := map[string]UpgradeAction{}
+	for _, file := range result.Files {
+		actions[file.Path] = file.Action
+	}
+	require.Equal(t, map[string]UpgradeAction{
+		"added.txt":    UpgradeAdded,
+		"conflict.txt": UpgradeConflict,
+		"dropped.txt":  UpgradeRemoved,
+		"edited.txt":   UpgradeMerged,
+		"gone.txt":     UpgradeConflict,
+		"local.txt":    UpgradeKept,
+	}, actions)
+	require.Equal(t, 2, result.Conflicts())
+	require.Equal(t, "1.0.0", result.FromVersion)
+	require.Equal(t, "2.0.0", result.ToVersion)
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(outputDir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+	require.Equal(t, "ONE\ntwo\nthree\nfour\n", read("edited.txt"))
+	require.Equal(t, "mine\n", read("local.txt"))
+	require.Equal(t, "new\n", read("added.txt"))
+	require.Equal(t, "<<<<<<< local\nz\n=======\ny\n>>>>>>> template 2.0.0\n", read("conflict.txt"))
+	require.Equal(t, "changed\n", read("gone.txt"+RejectSuffix))
+	require.NoFileExists(t, filepath.Join(outputDir, "dropped.txt"))
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(outputDir), ".*.mcp-*"))
+	require.NoError(t, err)
+	require.Empty(t, leftovers, "staging e backup removidos")
+
+	lock, err := ReadLockfile(outputDir)
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", lock.Version)
+	require.Equal(t, "one", lock.Values["name"])
+}
+
+func TestServiceUpgradeRequiresMaskedValues(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	outputDir := t.TempDir()
+	require.NoError(t, writeLockfile(outputDir, &models.Lockfile{
+		Template: "demo",
+		Version:  "1.0.0",
+		Values:   map[string]any{"api_token": MaskedValue},
+	}))
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mocks.NewMockRepository(ctrl))
+
+	_, err := service.Upgrade(context.Background(), UpgradeRequest{OutputDir: outputDir})
+	require.ErrorContains(t, err, "api_token")
+}
+
+func TestServiceUpgradeRunsHooks(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hooks := models.Hooks{PostRender: []models.HookStep{{Action: models.HookGofmt}}}
+	v1 := &models.TemplateMetadata{Name: "demo", Version: "1.0.0", Hooks: hooks}
+	v2 := &models.TemplateMetadata{Name: "demo", Version: "1.1.0", Hooks: hooks}
+	v1Dir := writeTemplateFiles(t, map[string]string{
+		"main.go": "package main\nfunc main()  {  }\n",
+	})
+	v2Dir := writeTemplateFiles(t, map[string]string{
+		"main.go": "package main\nfunc main()  {  }\nfunc extra()  {  }\n",
+	})
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(v1, v1Dir, nil).Times(1)
+	mockRepo.EXPECT().LoadTemplateVersion(gomock.Any(), "demo", "1.0.0").Return(v1, v1Dir, nil).Times(1)
+	mockRepo.EXPECT().LoadTemplateVersion(gomock.Any(), "demo", "1.1.0").Return(v2, v2Dir, nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	outputDir := t.TempDir()
+	_, err := service.Render(context.Background(), RenderRequest{TemplateName: "demo", OutputDir: outputDir})
+	require.NoError(t, err)
+
+	result, err := service.Upgrade(context.Background(), UpgradeRequest{OutputDir: outputDir, ToVersion: "1.1.0"})
+	require.NoError(t, err)
+	require.Equal(t, []UpgradedFile{{Path: "main.go", Action: UpgradeUpdated}}, result.Files, "arquivo alterado apenas pelo gofmt não é edição local")
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "main.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package main\n\nfunc main()  {}\nfunc extra() {}\n", string(data))
+	lock, err := ReadLockfile(outputDir)
+	require.NoError(t, err)
+	require.Equal(t, hashBytes(data), lock.Files["main.go"], "hash do arquivo promovido")
+}
@@ -0,0 +1,103 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// Fontes de valores definidas pelo próprio template.
+const (
+	SourceDefaults = "defaults"
+	SourceProfile  = "profile"
+)
+
+// ValueOrigin é um valor final e a fonte que o definiu.
+type ValueOrigin struct {
+	Path   string `json:"path"`
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+}
+
+// ValuesReport descreve os valores resolvidos de um template.
+type ValuesReport struct {
+	Template string        `json:"template"`
+	Profile  string        `json:"profile,omitempty"`
+	Values   []ValueOrigin `json:"values"`
+}
+
+// ExplainValues resolve os valores como Render faria, incluindo segredos e
+// variáveis computadas, sem validá-los, e informa de qual fonte veio cada folha
+// da árvore. Segredos são mascarados; os lidos de env: ou file: trazem a
+// referência na fonte.
+func (s *Service) ExplainValues(ctx context.Context, name, profile string, input []valuespkg.Layer, stdin io.Reader) (*ValuesReport, error) {
+	if name == "" {
+		return nil, errors.New("template name is required")
+	}
+
+	meta, templatePath, err := s.loadTemplate(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	layered, err := s.inherit(ctx, meta, templatePath)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := templateLayers(layered.meta, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, origins := valuespkg.Resolve(append(layers, input...))
+	secrets, err := resolveSecrets(layered.meta, tree, stdin)
+	if err != nil {
+		return nil, err
+	}
+	for path, ref := range secrets.refs {
+		origins[path] += " (" + ref + ")"
+	}
+	computed, err := computeValues(layered.meta, tree)
+	if err != nil {
+		return nil, secrets.redactError(err)
+	}
+	for _, key := range computed {
+		origins[key] = SourceComputed
+	}
+	tree = secrets.mask(tree, false)
+
+	report := &ValuesReport{Template: layered.meta.Name, Profile: profile, Values: make([]ValueOrigin, 0, len(origins))}
+	for path, source := range origins {
+		value, _ := valuespkg.Lookup(tree, path)
+		report.Values = append(report.Values, ValueOrigin{Path: path, Value: value, Source: source})
+	}
+	sort.Slice(report.Values, func(i, j int) bool { return report.Values[i].Path < report.Values[j].Path })
+	return report, nil
+}
+
+// templateLayers retorna as camadas de valores do template: defaults e, se
+// informado, o perfil selecionado.
+func templateLayers(meta *models.TemplateMetadata, profile string) ([]valuespkg.Layer, error) {
+	layers := []valuespkg.Layer{{Source: SourceDefaults, Values: meta.Defaults}}
+	if profile == "" {
+		return layers, nil
+	}
+	values, ok := meta.Profiles[profile]
+	if !ok {
+		available := make([]string, 0, len(meta.Profiles))
+		for key := range meta.Profiles {
+			available = append(available, key)
+		}
+		sort.Strings(available)
+		if len(available) == 0 {
+			return nil, fmt.Errorf("profile %q not defined: template %s has no profiles", profile, meta.Name)
+		}
+		return nil, fmt.Errorf("profile %q not defined in template %s (available: %s)", profile, meta.Name, strings.Join(available, ", "))
+	}
+	return append(layers, valuespkg.Layer{Source: SourceProfile + " " + profile, Values: values}), nil
+}
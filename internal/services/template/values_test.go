@@ -0,0 +1,100 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+func profiledMetadata() *models.TemplateMetadata {
+	return &models.TemplateMetadata{
+		Name: "demo",
+		Defaults: map[string]any{
+			"replicas":    1,
+			"log_level":   "debug",
+			"db_password": "dev",
+		},
+		Profiles: map[string]map[string]any{
+			"prod": {"replicas": 3, "log_level": "info"},
+		},
+		Computed: map[string]string{"instance": "{{ .log_level }}-{{ .replicas }}"},
+	}
+}
+
+func TestServiceRenderWithProfile(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	templateDir := writeTemplateFiles(t, map[string]string{
+		"config.txt.tmpl": "replicas={{ .replicas }} log={{ .log_level }}\n",
+	})
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(profiledMetadata(), templateDir, nil).AnyTimes()
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	outputDir := t.TempDir()
+	_, err := service.Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    outputDir,
+		Values:       map[string]any{"log_level": "warn"},
+		Profile:      "prod",
+		Overwrite:    true,
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "config.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "replicas=3 log=warn\n", string(data))
+
+	lock, err := ReadLockfile(outputDir)
+	require.NoError(t, err)
+	require.Equal(t, "prod", lock.Profile)
+
+	_, err = service.Render(context.Background(), RenderRequest{
+		TemplateName: "demo",
+		OutputDir:    t.TempDir(),
+		Profile:      "qa",
+		Overwrite:    true,
+	})
+	require.ErrorContains(t, err, `profile "qa" not defined in template demo (available: prod)`)
+}
+
+func TestServiceExplainValues(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "demo").Return(profiledMetadata(), t.TempDir(), nil).Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, testLogger(), prometheus.NewRegistry(), mockRepo)
+
+	report, err := service.ExplainValues(context.Background(), "demo", "prod", []valuespkg.Layer{
+		{Source: "values.yaml", Values: map[string]any{"log_level": "warn"}},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "prod", report.Profile)
+	require.Equal(t, []ValueOrigin{
+		{Path: "db_password", Value: MaskedValue, Source: SourceDefaults},
+		{Path: "instance", Value: "warn-3", Source: SourceComputed},
+		{Path: "log_level", Value: "warn", Source: "values.yaml"},
+		{Path: "replicas", Value: 3, Source: "profile prod"},
+	}, report.Values)
+}
@@ -0,0 +1,205 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// ValidationError agrega todas as variáveis que falharam na validação.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d variável(is) inválida(s):\n  - %s", len(e.Errors), strings.Join(msgs, "\n  - "))
+}
+
+// Unwrap expõe os erros individuais para errors.Is/errors.As.
+func (e *ValidationError) Unwrap() []error {
+	return e.Errors
+}
+
+// ValidateValue verifica um único valor contra a definição da variável.
+// Valores vazios só falham quando a variável é obrigatória.
+func ValidateValue(variable models.TemplateVariable, value string) error {
+	if value == "" {
+		if variable.Required {
+			return pkgtemplate.ErrMissingVariable{Key: variable.Key}
+		}
+		return nil
+	}
+
+	invalid := func(format string, args ...interface{}) error {
+		return pkgtemplate.ErrInvalidVariable{Key: variable.Key, Reason: fmt.Sprintf(format, args...)}
+	}
+	// shown é o valor citado nas mensagens; segredos nunca aparecem.
+	shown := value
+	if variable.Secret {
+		shown = MaskedValue
+	}
+
+	switch variable.Type {
+	case "", models.VariableTypeString:
+	case models.VariableTypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return invalid("esperado inteiro, recebido %q", shown)
+		}
+	case models.VariableTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return invalid("esperado booleano, recebido %q", shown)
+		}
+	case models.VariableTypeEnum:
+		if len(variable.Choices) == 0 {
+			return invalid("tipo enum sem choices definidos em template.yaml")
+		}
+		if !containsString(variable.Choices, value) {
+			return invalid("valor %q fora das opções [%s]", shown, strings.Join(variable.Choices, ", "))
+		}
+	case models.VariableTypeGoModule:
+		if err := checkModulePath(value); err != nil {
+			return invalid("módulo Go %q inválido: %v", shown, err)
+		}
+	case models.VariableTypeList, models.VariableTypeMap:
+		return invalid("esperado %s, recebido %q", variable.Type, shown)
+	default:
+		return invalid("tipo desconhecido %q em template.yaml", variable.Type)
+	}
+
+	length := utf8.RuneCountInString(value)
+	if variable.MinLength > 0 && length < variable.MinLength {
+		return invalid("tamanho mínimo %d, recebido %d", variable.MinLength, length)
+	}
+	if variable.MaxLength > 0 && length > variable.MaxLength {
+		return invalid("tamanho máximo %d, recebido %d", variable.MaxLength, length)
+	}
+
+	if variable.Pattern != "" {
+		re, err := regexp.Compile("^(?:" + variable.Pattern + ")$")
+		if err != nil {
+			return invalid("pattern inválido em template.yaml: %v", err)
+		}
+		if !re.MatchString(value) {
+			return invalid("valor %q não corresponde ao pattern %s", shown, variable.Pattern)
+		}
+	}
+
+	return nil
+}
+
+// validateVariables valida as variáveis declaradas. Com computed false, as que
+// também aparecem em computed são ignoradas, pois ainda não foram calculadas;
+// com true, apenas elas são validadas.
+func validateVariables(meta *models.TemplateMetadata, values map[string]any, computed bool) error {
+	var errs []error
+	for _, variable := range meta.Variables {
+		if _, ok := meta.Computed[variable.Key]; ok != computed {
+			continue
+		}
+		value, _ := valuespkg.Lookup(values, variable.Key)
+		if err := validateTree(variable, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validateTree valida um valor da árvore: variáveis list e map exigem a forma
+// correspondente; as demais exigem escalares, validados por ValidateValue.
+func validateTree(variable models.TemplateVariable, value any) error {
+	invalid := func(format string, args ...interface{}) error {
+		return pkgtemplate.ErrInvalidVariable{Key: variable.Key, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	switch variable.Type {
+	case models.VariableTypeList, models.VariableTypeMap:
+		var size int
+		switch typed := value.(type) {
+		case nil:
+		case []any:
+			if variable.Type != models.VariableTypeList {
+				return invalid("esperado map, recebida lista")
+			}
+			size = len(typed)
+		case map[string]any:
+			if variable.Type != models.VariableTypeMap {
+				return invalid("esperado list, recebido map")
+			}
+			size = len(typed)
+		default:
+			return ValidateValue(variable, valuespkg.String(value))
+		}
+		if size == 0 && variable.Required {
+			return pkgtemplate.ErrMissingVariable{Key: variable.Key}
+		}
+		return nil
+	}
+
+	if !valuespkg.IsScalar(value) {
+		if variable.Secret {
+			return invalid("esperado valor escalar")
+		}
+		return invalid("esperado valor escalar, recebido %s", valuespkg.String(value))
+	}
+	return ValidateValue(variable, valuespkg.String(value))
+}
+
+// checkModulePath aplica as regras de caminho de import do toolchain Go
+// (elementos separados por "/", sem espaços, esquemas ou elementos vazios).
+func checkModulePath(path string) error {
+	if strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		return fmt.Errorf("não pode iniciar ou terminar com /")
+	}
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "" {
+			return fmt.Errorf("elemento vazio")
+		}
+		if elem == "." || elem == ".." {
+			return fmt.Errorf("elemento %q não permitido", elem)
+		}
+		if strings.HasPrefix(elem, ".") || strings.HasSuffix(elem, ".") {
+			return fmt.Errorf("elemento %q não pode iniciar ou terminar com ponto", elem)
+		}
+		if strings.HasPrefix(elem, "-") {
+			return fmt.Errorf("elemento %q não pode iniciar com hífen", elem)
+		}
+		for _, r := range elem {
+			if !isModulePathRune(r) {
+				return fmt.Errorf("caractere %q não permitido", r)
+			}
+		}
+	}
+	return nil
+}
+
+func isModulePathRune(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return true
+	case r == '-', r == '.', r == '_', r == '~':
+		return true
+	}
+	return false
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package template
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	pkgtemplate "github.com/vertikon/mcp-ultra-templates/pkg/template"
+)
+
+func TestValidateValueTypes(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		variable models.TemplateVariable
+		value    string
+		valid    bool
+	}{
+		{"string", models.TemplateVariable{Key: "k"}, "anything", true},
+		{"int ok", models.TemplateVariable{Key: "k", Type: models.VariableTypeInt}, "42", true},
+		{"int bad", models.TemplateVariable{Key: "k", Type: models.VariableTypeInt}, "4x", false},
+		{"bool ok", models.TemplateVariable{Key: "k", Type: models.VariableTypeBool}, "true", true},
+		{"bool bad", models.TemplateVariable{Key: "k", Type: models.VariableTypeBool}, "sim", false},
+		{"enum ok", models.TemplateVariable{Key: "k", Type: models.VariableTypeEnum, Choices: []string{"dev", "prod"}}, "prod", true},
+		{"enum bad", models.TemplateVariable{Key: "k", Type: models.VariableTypeEnum, Choices: []string{"dev", "prod"}}, "qa", false},
+		{"enum without choices", models.TemplateVariable{Key: "k", Type: models.VariableTypeEnum}, "qa", false},
+		{"module ok", models.TemplateVariable{Key: "k", Type: models.VariableTypeGoModule}, "github.com/acme/svc-x", true},
+		{"module with space", models.TemplateVariable{Key: "k", Type: models.VariableTypeGoModule}, "github.com/acme/my svc", false},
+		{"module with scheme", models.TemplateVariable{Key: "k", Type: models.VariableTypeGoModule}, "https://github.com/acme/svc", false},
+		{"module trailing slash", models.TemplateVariable{Key: "k", Type: models.VariableTypeGoModule}, "github.com/acme/", false},
+		{"pattern ok", models.TemplateVariable{Key: "k", Pattern: "[a-z-]+"}, "my-svc", true},
+		{"pattern is anchored", models.TemplateVariable{Key: "k", Pattern: "[a-z-]+"}, "my-svc!", false},
+		{"bad pattern", models.TemplateVariable{Key: "k", Pattern: "("}, "x", false},
+		{"min length", models.TemplateVariable{Key: "k", MinLength: 3}, "ab", false},
+		{"max length", models.TemplateVariable{Key: "k", MaxLength: 3}, "abcd", false},
+		{"unknown type", models.TemplateVariable{Key: "k", Type: "float"}, "1.0", false},
+		{"optional empty", models.TemplateVariable{Key: "k", Type: models.VariableTypeInt}, "", true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateValue(tc.variable, tc.value)
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestValidateVariablesReportsAllFailures(t *testing.T) {
+	t.Parallel()
+
+	meta := &models.TemplateMetadata{
+		Variables: []models.TemplateVariable{
+			{Key: "module_name", Type: models.VariableTypeGoModule},
+			{Key: "replicas", Type: models.VariableTypeInt},
+			{Key: "env", Required: true},
+			{Key: "ok"},
+		},
+	}
+
+	err := validateVariables(meta, map[string]any{
+		"module_name": "github.com/acme/bad module",
+		"replicas":    "three",
+		"ok":          "fine",
+	}, false)
+	require.Error(t, err)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Errors, 3)
+	require.Contains(t, err.Error(), "module_name")
+	require.Contains(t, err.Error(), "replicas")
+	require.Contains(t, err.Error(), "env")
+
+	var missing pkgtemplate.ErrMissingVariable
+	require.True(t, errors.As(err, &missing))
+	require.Equal(t, "env", missing.Key)
+}
+
+func TestValidateVariablesNested(t *testing.T) {
+	t.Parallel()
+
+	meta := &models.TemplateMetadata{
+		Variables: []models.TemplateVariable{
+			{Key: "services", Type: models.VariableTypeList, Required: true},
+			{Key: "labels", Type: models.VariableTypeMap},
+			{Key: "database.port", Type: models.VariableTypeInt},
+		},
+	}
+
+	require.NoError(t, validateVariables(meta, map[string]any{
+		"services": []any{map[string]any{"name": "users"}},
+		"labels":   map[string]any{"team": "core"},
+		"database": map[string]any{"port": 5432},
+	}, false))
+
+	err := validateVariables(meta, map[string]any{
+		"services": []any{},
+		"labels":   []any{"team"},
+		"database": map[string]any{"port": "x"},
+	}, false)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Errors, 3)
+	require.Contains(t, err.Error(), "database.port")
+}
@@ -0,0 +1,82 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/pkg/semver"
+)
+
+// LatestVersion seleciona explicitamente, em <nome>@latest, a versão atual do
+// repositório, a mesma usada quando a referência não informa versão.
+const LatestVersion = "latest"
+
+// SplitTemplateRef separa uma referência <nome>@<versão>. A versão pode ser
+// exata ("1.2.0"), uma restrição semver ("^1.1", ">=1.0 <2") ou LatestVersion;
+// vazia equivale a LatestVersion.
+func SplitTemplateRef(ref string) (name, version string) {
+	name, version, _ = strings.Cut(ref, "@")
+	return name, version
+}
+
+// loadTemplate carrega o template referenciado por ref, no formato aceito por
+// SplitTemplateRef.
+func (s *Service) loadTemplate(ctx context.Context, ref string) (*models.TemplateMetadata, string, error) {
+	name, version := SplitTemplateRef(ref)
+	return s.loadVersion(ctx, name, version)
+}
+
+// loadVersion carrega name na versão pedida. Vazia ou LatestVersion usam
+// LoadTemplate; uma versão completa é carregada diretamente; as demais
+// restrições semver escolhem a maior versão disponível que as satisfaz, sem
+// prereleases a menos que a restrição cite uma. Valores que não são semver
+// são tratados como versão exata.
+func (s *Service) loadVersion(ctx context.Context, name, version string) (*models.TemplateMetadata, string, error) {
+	if version == "" || version == LatestVersion {
+		return s.repo.LoadTemplate(ctx, name)
+	}
+	if v, err := semver.Parse(version); err == nil && v.String() == strings.TrimPrefix(version, "v") {
+		return s.repo.LoadTemplateVersion(ctx, name, version)
+	}
+	constraint, err := semver.ParseConstraint(version)
+	if err != nil {
+		return s.repo.LoadTemplateVersion(ctx, name, version)
+	}
+
+	available, err := s.repo.ListVersions(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	resolved, ok := semver.Latest(available, constraint)
+	if !ok {
+		if len(available) == 0 {
+			return nil, "", fmt.Errorf("template %s has no versions to satisfy %s", name, version)
+		}
+		sortVersions(available)
+		return nil, "", fmt.Errorf("no version of template %s satisfies %s (available: %s)", name, version, strings.Join(available, ", "))
+	}
+	return s.repo.LoadTemplateVersion(ctx, name, resolved)
+}
+
+// Versions lista as versões disponíveis de name, da mais recente para a mais
+// antiga; versões que não são semver ficam no fim.
+func (s *Service) Versions(ctx context.Context, name string) ([]string, error) {
+	versions, err := s.repo.ListVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	sortVersions(versions)
+	return versions, nil
+}
+
+func sortVersions(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		if c := compareVersions(versions[i], versions[j]); c != 0 {
+			return c > 0
+		}
+		return versions[i] < versions[j]
+	})
+}
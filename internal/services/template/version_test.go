@@ -0,0 +1,100 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vertikon/mcp-ultra-templates/internal/config"
+	"github.com/vertikon/mcp-ultra-templates/internal/models"
+	"github.com/vertikon/mcp-ultra-templates/internal/services/template/mocks"
+)
+
+func TestSplitTemplateRef(t *testing.T) {
+	t.Parallel()
+
+	for ref, expected := range map[string][2]string{
+		"mcp":        {"mcp", ""},
+		"mcp@^1.1":   {"mcp", "^1.1"},
+		"mcp@latest": {"mcp", LatestVersion},
+		"mcp@1.0.0":  {"mcp", "1.0.0"},
+	} {
+		name, version := SplitTemplateRef(ref)
+		require.Equal(t, expected, [2]string{name, version}, ref)
+	}
+}
+
+func TestServiceLoadVersion(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockRepository(ctrl)
+	load := func(version string) *gomock.Call {
+		return mockRepo.EXPECT().LoadTemplateVersion(gomock.Any(), "mcp", version).
+			Return(&models.TemplateMetadata{Name: "mcp", Version: version}, "/templates/mcp@"+version, nil)
+	}
+	mockRepo.EXPECT().ListVersions(gomock.Any(), "mcp").Return([]string{"1.0.0", "1.1.0", "1.1.3", "1.2.0", "2.0.0-rc.1"}, nil).AnyTimes()
+	mockRepo.EXPECT().LoadTemplate(gomock.Any(), "mcp").Return(&models.TemplateMetadata{Name: "mcp", Version: "1.2.0"}, "/templates/mcp", nil).Times(2)
+	load("1.2.0").Times(1)
+	load("1.1.3").Times(1)
+	load("1.0.0").Times(2)
+	load("2.0.0-rc.1").Times(1)
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, zerolog.Nop(), prometheus.NewRegistry(), mockRepo)
+	ctx := context.Background()
+
+	for ref, version := range map[string]string{
+		"mcp":                "1.2.0",
+		"mcp@latest":         "1.2.0",
+		"mcp@^1.1":           "1.2.0",
+		"mcp@~1.1":           "1.1.3",
+		"mcp@1.0.0":          "1.0.0",
+		"mcp@>=2.0.0-rc.0":   "2.0.0-rc.1",
+		"mcp@>=1.0.0 <1.1.0": "1.0.0",
+	} {
+		meta, _, err := service.loadTemplate(ctx, ref)
+		require.NoError(t, err, ref)
+		require.Equal(t, version, meta.Version, ref)
+	}
+
+	_, _, err := service.loadTemplate(ctx, "mcp@^3")
+	require.ErrorContains(t, err, "no version of template mcp satisfies ^3 (available: 2.0.0-rc.1, 1.2.0, 1.1.3, 1.1.0, 1.0.0)")
+
+	versions, err := service.Versions(ctx, "mcp")
+	require.NoError(t, err)
+	require.Equal(t, []string{"2.0.0-rc.1", "1.2.0", "1.1.3", "1.1.0", "1.0.0"}, versions)
+}
+
+func TestServiceListAllVersions(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockRepo.EXPECT().ListTemplates(gomock.Any()).Return([]models.TemplateMetadata{
+		{Name: "sdk", Version: "0.3.0"},
+		{Name: "mcp", Version: "1.2.0"},
+	}, nil)
+	mockRepo.EXPECT().ListVersions(gomock.Any(), "sdk").Return([]string{"0.3.0"}, nil)
+	mockRepo.EXPECT().ListVersions(gomock.Any(), "mcp").Return([]string{"1.0.0", "v1.2.0", "1.1.0"}, nil)
+	mockRepo.EXPECT().LoadTemplateVersion(gomock.Any(), "mcp", "1.1.0").Return(&models.TemplateMetadata{Name: "mcp", Version: "1.1.0"}, "", nil)
+	mockRepo.EXPECT().LoadTemplateVersion(gomock.Any(), "mcp", "1.0.0").Return(nil, "", errors.New("unmarshal metadata"))
+
+	cfg := config.RenderingConfig{OperationTimeout: 5 * time.Second, MaxRetryAttempts: 1}
+	service := New(cfg, zerolog.Nop(), prometheus.NewRegistry(), mockRepo)
+
+	result, err := service.List(context.Background(), ListOptions{AllVersions: true})
+	require.NoError(t, err)
+	var refs []string
+	for _, tmpl := range result.Templates {
+		refs = append(refs, tmpl.Name+"@"+tmpl.Version)
+	}
+	require.Equal(t, []string{"mcp@1.2.0", "mcp@1.1.0", "sdk@0.3.0"}, refs)
+	require.Equal(t, []models.SkippedTemplate{{Name: "mcp@1.0.0", Reason: "unmarshal metadata"}}, result.Skipped)
+}
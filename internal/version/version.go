@@ -0,0 +1,6 @@
+// Package version expõe a versão da CLI, definida em tempo de build via -ldflags.
+package version
+
+// Version é sobrescrita no build com
+// -ldflags "-X github.com/vertikon/mcp-ultra-templates/internal/version.Version=v1.2.3".
+var Version = "dev"
@@ -0,0 +1,256 @@
+// Package diff implementa diff de linhas (Myers em espaço linear) e saída no formato unificado.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const contextLines = 3
+
+// Match associa a linha A[A] à linha B[B] em uma subsequência comum máxima.
+type Match struct {
+	A, B int
+}
+
+// SplitLines separa o conteúdo em linhas preservando o terminador "\n".
+func SplitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Matches retorna os pares de linhas iguais, em ordem crescente, que compõem
+// uma subsequência comum máxima entre a e b.
+func Matches(a, b []string) []Match {
+	d := &differ{a: a, b: b}
+	d.compare(0, len(a), 0, len(b))
+	return d.matches
+}
+
+// Unified gera um diff unificado entre a e b. Retorna vazio quando o conteúdo é igual.
+func Unified(fromName, toName string, a, b []byte) string {
+	if string(a) == string(b) {
+		return ""
+	}
+	if IsBinary(a) || IsBinary(b) {
+		return fmt.Sprintf("Binary files %s and %s differ\n", fromName, toName)
+	}
+
+	linesA, linesB := SplitLines(a), SplitLines(b)
+	ops := editScript(linesA, linesB, Matches(linesA, linesB))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for _, h := range hunks(ops) {
+		writeHunk(&out, h, linesA, linesB)
+	}
+	return out.String()
+}
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+type op struct {
+	kind opKind
+	a, b int // posição em A e B antes da operação
+}
+
+func editScript(a, b []string, matches []Match) []op {
+	ops := make([]op, 0, len(a)+len(b))
+	i, j := 0, 0
+	for _, m := range append(matches, Match{A: len(a), B: len(b)}) {
+		for ; i < m.A; i++ {
+			ops = append(ops, op{kind: opDelete, a: i, b: j})
+		}
+		for ; j < m.B; j++ {
+			ops = append(ops, op{kind: opInsert, a: i, b: j})
+		}
+		if i < len(a) && j < len(b) {
+			ops = append(ops, op{kind: opEqual, a: i, b: j})
+			i++
+			j++
+		}
+	}
+	return ops
+}
+
+func hunks(ops []op) [][]op {
+	var result [][]op
+	start := -1
+	lastChange := -1
+	for idx, o := range ops {
+		if o.kind == opEqual {
+			if start >= 0 && idx-lastChange > 2*contextLines {
+				result = append(result, ops[start:lastChange+contextLines+1])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = idx - contextLines
+			if start < 0 {
+				start = 0
+			}
+		}
+		lastChange = idx
+	}
+	if start >= 0 {
+		end := lastChange + contextLines + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+		result = append(result, ops[start:end])
+	}
+	return result
+}
+
+func writeHunk(out *strings.Builder, h []op, a, b []string) {
+	countA, countB := 0, 0
+	for _, o := range h {
+		if o.kind != opInsert {
+			countA++
+		}
+		if o.kind != opDelete {
+			countB++
+		}
+	}
+	startA, startB := h[0].a, h[0].b
+	if countA > 0 {
+		startA++
+	}
+	if countB > 0 {
+		startB++
+	}
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", startA, countA, startB, countB)
+	for _, o := range h {
+		line := ""
+		switch o.kind {
+		case opInsert:
+			line = b[o.b]
+		default:
+			line = a[o.a]
+		}
+		out.WriteByte(byte(o.kind))
+		out.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// IsBinary indica se o conteúdo parece binário (byte nulo nos primeiros 8000 bytes).
+func IsBinary(data []byte) bool {
+	sample := data
+	if len(sample) > 8000 {
+		sample = sample[:8000]
+	}
+	for _, c := range sample {
+		if c == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+type differ struct {
+	a, b    []string
+	matches []Match
+}
+
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.matches = append(d.matches, Match{A: aLo, B: bLo})
+		aLo++
+		bLo++
+	}
+	suffixA := aHi
+	for aLo < aHi && bLo < bHi && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+	}
+
+	if aLo < aHi && bLo < bHi {
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		for i := 0; i < u-x; i++ {
+			d.matches = append(d.matches, Match{A: x + i, B: y + i})
+		}
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for i := 0; aHi+i < suffixA; i++ {
+		d.matches = append(d.matches, Match{A: aHi + i, B: bHi + i})
+	}
+}
+
+// middleSnake localiza o trecho diagonal central de um caminho de edição mínimo
+// (Myers, 1986), permitindo dividir o problema em duas metades.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (int, int, int, int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	off := limit + 1
+	vf := make([]int, 2*off+1)
+	vb := make([]int, 2*off+1)
+
+	for depth := 0; depth <= limit; depth++ {
+		for k := -depth; k <= depth; k += 2 {
+			var x int
+			if k == -depth || (k != depth && vf[off+k-1] < vf[off+k+1]) {
+				x = vf[off+k+1]
+			} else {
+				x = vf[off+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.a[aLo+x] == d.b[bLo+y] {
+				x++
+				y++
+			}
+			vf[off+k] = x
+			if odd {
+				kb := delta - k
+				if kb >= -(depth-1) && kb <= depth-1 && vf[off+k]+vb[off+kb] >= n {
+					return aLo + sx, bLo + sy, aLo + x, bLo + y
+				}
+			}
+		}
+
+		for k := -depth; k <= depth; k += 2 {
+			var x int
+			if k == -depth || (k != depth && vb[off+k-1] < vb[off+k+1]) {
+				x = vb[off+k+1]
+			} else {
+				x = vb[off+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.a[aHi-1-x] == d.b[bHi-1-y] {
+				x++
+				y++
+			}
+			vb[off+k] = x
+			if !odd {
+				kf := delta - k
+				if kf >= -depth && kf <= depth && vb[off+k]+vf[off+kf] >= n {
+					return aLo + n - x, bLo + m - y, aLo + n - sx, bLo + m - sy
+				}
+			}
+		}
+	}
+
+	// Inalcançável: dois caminhos de edição sempre se encontram em até limit passos.
+	panic("diff: middle snake not found")
+}
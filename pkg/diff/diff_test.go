@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	expected := `--- a/file.txt
++++ b/file.txt
+@@ -1,6 +1,6 @@
+ one
+ two
+-three
++THREE
+ four
+ five
+ six
+@@ -8,3 +8,4 @@
+ eight
+ nine
+ ten
++eleven
+`
+	require.Equal(t, expected, Unified("a/file.txt", "b/file.txt", []byte(a), []byte(b)))
+}
+
+func TestUnifiedEdgeCases(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, Unified("a", "b", []byte("same\n"), []byte("same\n")))
+	require.Equal(t, "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+new\n", Unified("a", "b", nil, []byte("new\n")))
+	require.Equal(t, "--- a\n+++ b\n@@ -1,1 +0,0 @@\n-old\n", Unified("a", "b", []byte("old\n"), nil))
+	require.Equal(t, "--- a\n+++ b\n@@ -1,1 +1,1 @@\n-x\n+x\n\\ No newline at end of file\n", Unified("a", "b", []byte("x\n"), []byte("x")))
+	require.Equal(t, "Binary files a and b differ\n", Unified("a", "b", []byte{0, 1}, []byte{0, 2}))
+}
+
+func TestMatchesIsLongestCommonSubsequence(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewSource(42))
+	alphabet := []string{"a\n", "b\n", "c\n", "d\n"}
+	for iter := 0; iter < 500; iter++ {
+		a := randomLines(rng, alphabet, rng.Intn(30))
+		b := randomLines(rng, alphabet, rng.Intn(30))
+
+		matches := Matches(a, b)
+		require.Len(t, matches, lcsLength(a, b), "a=%q b=%q", strings.Join(a, ""), strings.Join(b, ""))
+		for i, m := range matches {
+			require.Equal(t, a[m.A], b[m.B])
+			if i > 0 {
+				require.Greater(t, m.A, matches[i-1].A)
+				require.Greater(t, m.B, matches[i-1].B)
+			}
+		}
+	}
+}
+
+func randomLines(rng *rand.Rand, alphabet []string, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = alphabet[rng.Intn(len(alphabet))]
+	}
+	return lines
+}
+
+func lcsLength(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				dp[i][j] = dp[i+1][j+1] + 1
+			case dp[i+1][j] > dp[i][j+1]:
+				dp[i][j] = dp[i+1][j]
+			default:
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+	return dp[0][0]
+}
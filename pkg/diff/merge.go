@@ -0,0 +1,97 @@
+package diff
+
+import "strings"
+
+// Marcadores de conflito no formato usado pelo git.
+const (
+	markerOurs   = "<<<<<<<"
+	markerSep    = "======="
+	markerTheirs = ">>>>>>>"
+)
+
+// Merge combina as alterações de ours e theirs sobre base linha a linha (diff3).
+// Trechos alterados apenas de um lado são aplicados diretamente; trechos alterados
+// de formas diferentes nos dois lados viram blocos de conflito rotulados com
+// oursName e theirsName. O segundo retorno indica se houve conflito.
+func Merge(base, ours, theirs []byte, oursName, theirsName string) ([]byte, bool) {
+	o, a, b := SplitLines(base), SplitLines(ours), SplitLines(theirs)
+	toA := matchIndex(len(o), Matches(o, a))
+	toB := matchIndex(len(o), Matches(o, b))
+
+	var out strings.Builder
+	conflict := false
+	io, ia, ib := 0, 0, 0
+	for {
+		// Região estável: linhas da base presentes, na mesma sequência, nos dois lados.
+		for io < len(o) && toA[io] == ia && toB[io] == ib {
+			out.WriteString(o[io])
+			io++
+			ia++
+			ib++
+		}
+		if io == len(o) && ia == len(a) && ib == len(b) {
+			break
+		}
+
+		jo := io
+		for jo < len(o) && (toA[jo] < 0 || toB[jo] < 0) {
+			jo++
+		}
+		ja, jb := len(a), len(b)
+		if jo < len(o) {
+			ja, jb = toA[jo], toB[jo]
+		}
+
+		chunkO, chunkA, chunkB := o[io:jo], a[ia:ja], b[ib:jb]
+		switch {
+		case equalLines(chunkA, chunkO):
+			writeLines(&out, chunkB, false)
+		case equalLines(chunkB, chunkO), equalLines(chunkA, chunkB):
+			writeLines(&out, chunkA, false)
+		default:
+			conflict = true
+			out.WriteString(markerOurs + " " + oursName + "\n")
+			writeLines(&out, chunkA, true)
+			out.WriteString(markerSep + "\n")
+			writeLines(&out, chunkB, true)
+			out.WriteString(markerTheirs + " " + theirsName + "\n")
+		}
+		io, ia, ib = jo, ja, jb
+	}
+	return []byte(out.String()), conflict
+}
+
+// matchIndex mapeia cada linha da base para a linha correspondente do outro lado, ou -1.
+func matchIndex(n int, matches []Match) []int {
+	index := make([]int, n)
+	for i := range index {
+		index[i] = -1
+	}
+	for _, m := range matches {
+		index[m.A] = m.B
+	}
+	return index
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// writeLines escreve as linhas; terminate garante "\n" ao final, necessário
+// antes de um marcador de conflito.
+func writeLines(out *strings.Builder, lines []string, terminate bool) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+	if terminate && len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		out.WriteByte('\n')
+	}
+}
@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeClean(t *testing.T) {
+	t.Parallel()
+
+	base := "a\nb\nc\nd\ne\n"
+	ours := "a\nB\nc\nd\ne\n"
+	theirs := "a\nb\nc\nd\nE\nf\n"
+
+	merged, conflict := Merge([]byte(base), []byte(ours), []byte(theirs), "local", "template")
+	require.False(t, conflict)
+	require.Equal(t, "a\nB\nc\nd\nE\nf\n", string(merged))
+}
+
+func TestMergeOneSided(t *testing.T) {
+	t.Parallel()
+
+	base := []byte("x\ny\n")
+	changed := []byte("x\nY\nz\n")
+
+	merged, conflict := Merge(base, base, changed, "local", "template")
+	require.False(t, conflict)
+	require.Equal(t, string(changed), string(merged))
+
+	merged, conflict = Merge(base, changed, base, "local", "template")
+	require.False(t, conflict)
+	require.Equal(t, string(changed), string(merged))
+
+	merged, conflict = Merge(base, changed, changed, "local", "template")
+	require.False(t, conflict)
+	require.Equal(t, string(changed), string(merged))
+}
+
+func TestMergeConflict(t *testing.T) {
+	t.Parallel()
+
+	base := "a\nb\nc\n"
+	ours := "a\nlocal\nc\n"
+	theirs := "a\nupstream\nc\n"
+
+	merged, conflict := Merge([]byte(base), []byte(ours), []byte(theirs), "local", "template 1.1.0")
+	require.True(t, conflict)
+	require.Equal(t, "a\n<<<<<<< local\nlocal\n=======\nupstream\n>>>>>>> template 1.1.0\nc\n", string(merged))
+
+	merged, conflict = Merge([]byte("a\nb"), []byte("a\nlocal"), []byte("a\nup"), "local", "template")
+	require.True(t, conflict)
+	require.Equal(t, "a\n<<<<<<< local\nlocal\n=======\nup\n>>>>>>> template\n", string(merged))
+}
+
+func TestMergeBothAddedFromEmptyBase(t *testing.T) {
+	t.Parallel()
+
+	merged, conflict := Merge(nil, []byte("one\n"), []byte("two\n"), "local", "template")
+	require.True(t, conflict)
+	require.Equal(t, "<<<<<<< local\none\n=======\ntwo\n>>>>>>> template\n", string(merged))
+}
@@ -0,0 +1,202 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint é uma restrição de versões: alternativas separadas por "||", cada
+// uma formada por comparações separadas por espaço ou vírgula que devem valer
+// ao mesmo tempo.
+//
+// Operadores: =, !=, >, >=, <, <=, ^ (compatível: mesma versão major, ou minor
+// quando major é zero) e ~ (mesma minor). Sem operador, a versão informada de
+// forma parcial casa com todo o intervalo: "1.2" equivale a ">=1.2.0 <1.3.0".
+// Versões prerelease só casam com comparações que citam uma prerelease da
+// mesma major.minor.patch.
+type Constraint struct {
+	raw  string
+	alts [][]comparison
+}
+
+type comparison struct {
+	op      string
+	version *Version
+}
+
+// ParseConstraint interpreta s.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: s}
+	for _, alt := range strings.Split(s, "||") {
+		fields := strings.FieldsFunc(alt, func(r rune) bool { return r == ' ' || r == ',' || r == '\t' })
+		fields = joinOperators(fields)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid constraint %q", s)
+		}
+		var group []comparison
+		for _, field := range fields {
+			comparisons, err := parseComparison(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+			}
+			group = append(group, comparisons...)
+		}
+		c.alts = append(c.alts, group)
+	}
+	return c, nil
+}
+
+// joinOperators junta operadores separados da versão por espaço, como ">= 1.2".
+func joinOperators(fields []string) []string {
+	var result []string
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if strings.Trim(field, "<>=!^~") == "" && i+1 < len(fields) {
+			field += fields[i+1]
+			i++
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+func parseComparison(field string) ([]comparison, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(field, candidate) {
+			op = candidate
+			break
+		}
+	}
+	raw := strings.TrimSpace(field[len(op):])
+	if raw == "*" || raw == "x" || raw == "X" {
+		if op == "" || op == "=" {
+			return []comparison{{op: ">=", version: &Version{}}}, nil
+		}
+		return nil, fmt.Errorf("wildcard requires no operator: %s", field)
+	}
+	raw = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(raw, ".*"), ".x"), ".X")
+	v, parts, err := parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "^":
+		upper := &Version{Major: v.Major + 1}
+		switch {
+		case v.Major == 0 && parts >= 2 && v.Minor > 0:
+			upper = &Version{Minor: v.Minor + 1}
+		case v.Major == 0 && parts == 3:
+			upper = &Version{Patch: v.Patch + 1}
+		case v.Major == 0 && parts == 2:
+			upper = &Version{Minor: 1}
+		}
+		return []comparison{{">=", v}, {"<", upper}}, nil
+	case "~":
+		upper := &Version{Major: v.Major, Minor: v.Minor + 1}
+		if parts == 1 {
+			upper = &Version{Major: v.Major + 1}
+		}
+		return []comparison{{">=", v}, {"<", upper}}, nil
+	case "", "=":
+		if parts == 3 {
+			return []comparison{{"=", v}}, nil
+		}
+		upper := &Version{Major: v.Major + 1}
+		if parts == 2 {
+			upper = &Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+		return []comparison{{">=", v}, {"<", upper}}, nil
+	}
+	return []comparison{{op, v}}, nil
+}
+
+// Check informa se v satisfaz a restrição.
+func (c *Constraint) Check(v *Version) bool {
+	for _, group := range c.alts {
+		if checkGroup(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkGroup(group []comparison, v *Version) bool {
+	prereleaseAllowed := v.Prerelease == ""
+	for _, cmp := range group {
+		if !cmp.check(v) {
+			return false
+		}
+		if v.Prerelease != "" && cmp.version.Prerelease != "" &&
+			cmp.version.Major == v.Major && cmp.version.Minor == v.Minor && cmp.version.Patch == v.Patch {
+			prereleaseAllowed = true
+		}
+	}
+	return prereleaseAllowed
+}
+
+func (c comparison) check(v *Version) bool {
+	result := v.Compare(c.version)
+	switch c.op {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+	return false
+}
+
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// Satisfies interpreta version e constraint e informa se a versão satisfaz a restrição.
+func Satisfies(constraint, version string) (bool, error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+	return c.Check(v), nil
+}
+
+// Latest retorna a maior versão de versions que satisfaz c, ignorando as que não
+// são semver válidas. Com c nil, vale a maior versão estável ou, se só houver
+// prereleases, a maior delas. ok é false quando nenhuma versão atende.
+func Latest(versions []string, c *Constraint) (string, bool) {
+	var (
+		best       *Version
+		bestRaw    string
+		stableSeen bool
+	)
+	for _, raw := range versions {
+		v, err := Parse(raw)
+		if err != nil {
+			continue
+		}
+		if c != nil && !c.Check(v) {
+			continue
+		}
+		stable := v.Prerelease == ""
+		if c == nil && stableSeen && !stable {
+			continue
+		}
+		if best == nil || (c == nil && stable && !stableSeen) || v.Compare(best) > 0 {
+			best, bestRaw = v, raw
+		}
+		stableSeen = stableSeen || stable
+	}
+	return bestRaw, best != nil
+}
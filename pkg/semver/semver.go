@@ -0,0 +1,195 @@
+// Package semver interpreta e compara versões no formato Semantic Versioning
+// 2.0.0 e avalia restrições como ">=1.2.0 <2.0.0", "^1.4" e "~1.2.3".
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version é uma versão semântica. O prefixo "v" é aceito e descartado.
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease string
+	Metadata   string
+}
+
+// Parse interpreta s. Minor e patch ausentes valem zero, como em "1.2".
+func Parse(s string) (*Version, error) {
+	v, _, err := parse(s)
+	return v, err
+}
+
+// parse retorna também quantos componentes numéricos foram informados.
+func parse(s string) (*Version, int, error) {
+	original := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if s == "" {
+		return nil, 0, fmt.Errorf("invalid version %q", original)
+	}
+
+	v := &Version{}
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		v.Metadata = s[i+1:]
+		s = s[:i]
+		if !validIdentifiers(v.Metadata, false) {
+			return nil, 0, fmt.Errorf("invalid version %q: bad build metadata", original)
+		}
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.Prerelease = s[i+1:]
+		s = s[:i]
+		if !validIdentifiers(v.Prerelease, true) {
+			return nil, 0, fmt.Errorf("invalid version %q: bad prerelease", original)
+		}
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return nil, 0, fmt.Errorf("invalid version %q", original)
+	}
+	fields := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		if !numeric(part) || (len(part) > 1 && part[0] == '0') {
+			return nil, 0, fmt.Errorf("invalid version %q", original)
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid version %q: %w", original, err)
+		}
+		*fields[i] = n
+	}
+	return v, len(parts), nil
+}
+
+// MustParse é como Parse, mas entra em pânico em caso de erro. Útil em testes.
+func MustParse(s string) *Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (v *Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Metadata != "" {
+		s += "+" + v.Metadata
+	}
+	return s
+}
+
+// Compare retorna -1, 0 ou 1 conforme v seja menor, igual ou maior que o.
+// Metadados de build não influenciam a ordem.
+func (v *Version) Compare(o *Version) int {
+	for _, pair := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// Compare interpreta e compara duas versões.
+func Compare(a, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// Equal informa se a e b são a mesma versão: comparadas como semver quando
+// ambas são válidas, de modo que "v1.2.0" e "1.2.0" coincidem, e como texto
+// caso contrário.
+func Equal(a, b string) bool {
+	if a == b {
+		return true
+	}
+	c, err := Compare(a, b)
+	return err == nil && c == 0
+}
+
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		an, bn := numeric(as[i]), numeric(bs[i])
+		switch {
+		case an && bn:
+			x, _ := strconv.ParseUint(as[i], 10, 64)
+			y, _ := strconv.ParseUint(bs[i], 10, 64)
+			if x < y {
+				return -1
+			}
+			return 1
+		case an:
+			return -1
+		case bn:
+			return 1
+		case as[i] < bs[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+func numeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func validIdentifiers(s string, noLeadingZero bool) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+		if noLeadingZero && numeric(id) && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
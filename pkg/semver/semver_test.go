@@ -0,0 +1,128 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	v, err := Parse("v1.2.3-rc.1+build.5")
+	require.NoError(t, err)
+	require.Equal(t, &Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1", Metadata: "build.5"}, v)
+	require.Equal(t, "1.2.3-rc.1+build.5", v.String())
+
+	v, err = Parse("1.4")
+	require.NoError(t, err)
+	require.Equal(t, "1.4.0", v.String())
+
+	for _, invalid := range []string{"", "v", "1.2.3.4", "01.2.3", "1.a.3", "1.2.3-", "1.2.3-01", "1.2.3+"} {
+		_, err := Parse(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+	for i := 0; i+1 < len(ordered); i++ {
+		result, err := Compare(ordered[i], ordered[i+1])
+		require.NoError(t, err)
+		require.Equal(t, -1, result, "%s < %s", ordered[i], ordered[i+1])
+		require.Equal(t, 1, MustParse(ordered[i+1]).Compare(MustParse(ordered[i])))
+	}
+
+	result, err := Compare("1.0.0+a", "v1.0.0+b")
+	require.NoError(t, err)
+	require.Zero(t, result)
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Equal("v1.2.0", "1.2.0"))
+	require.True(t, Equal("1.2", "1.2.0+build"))
+	require.True(t, Equal("latest", "latest"))
+	require.False(t, Equal("1.2.0", "1.2.0-rc.1"))
+	require.False(t, Equal("1.2.0", "next"))
+}
+
+func TestConstraint(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{">=1.2.0 <2.0.0", "1.9.9", true},
+		{">=1.2.0, <2.0.0", "2.0.0", false},
+		{">= 1.2", "1.2.0", true},
+		{"^1.4", "1.9.0", true},
+		{"^1.4", "2.0.0", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~1.2.3", "1.2.9", true},
+		{"~1.2.3", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"1.2", "1.2.7", true},
+		{"1.2", "1.3.0", false},
+		{"=1.2.3", "1.2.3", true},
+		{"!=1.2.3", "1.2.3", false},
+		{"<1.0.0 || >=3.0.0", "3.1.0", true},
+		{"<1.0.0 || >=3.0.0", "2.0.0", false},
+		{"*", "0.0.1", true},
+		{"1.x", "1.5.0", true},
+		{">=1.0.0", "1.1.0-beta", false},
+		{">=1.1.0-alpha", "1.1.0-beta", true},
+		{">=1.1.0-alpha", "1.2.0-beta", false},
+	}
+	for _, tc := range cases {
+		ok, err := Satisfies(tc.constraint, tc.version)
+		require.NoError(t, err, tc.constraint)
+		require.Equal(t, tc.expected, ok, "%s satisfies %s", tc.version, tc.constraint)
+	}
+
+	for _, invalid := range []string{"", ">=", "^abc", "1.2 || ", ">*"} {
+		_, err := ParseConstraint(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	t.Parallel()
+
+	versions := []string{"1.0.0", "v1.2.0", "1.10.0", "2.0.0-rc.1", "next", "1.2.5"}
+
+	latest, ok := Latest(versions, nil)
+	require.True(t, ok)
+	require.Equal(t, "1.10.0", latest, "prereleases only without stable versions")
+
+	latest, ok = Latest([]string{"2.0.0-rc.1", "2.0.0-beta"}, nil)
+	require.True(t, ok)
+	require.Equal(t, "2.0.0-rc.1", latest)
+
+	latest, ok = Latest(versions, mustConstraint(t, "~1.2"))
+	require.True(t, ok)
+	require.Equal(t, "1.2.5", latest)
+
+	latest, ok = Latest(versions, mustConstraint(t, ">=2.0.0-rc.0"))
+	require.True(t, ok)
+	require.Equal(t, "2.0.0-rc.1", latest)
+
+	_, ok = Latest(versions, mustConstraint(t, "^3"))
+	require.False(t, ok)
+	_, ok = Latest(nil, nil)
+	require.False(t, ok)
+}
+
+func mustConstraint(t *testing.T, s string) *Constraint {
+	t.Helper()
+	c, err := ParseConstraint(s)
+	require.NoError(t, err)
+	return c
+}
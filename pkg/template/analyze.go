@@ -0,0 +1,312 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// builtinFuncs são as funções predefinidas de text/template.
+var builtinFuncs = []string{
+	"and", "or", "not", "len", "index", "slice", "print", "printf", "println",
+	"html", "js", "urlquery", "call", "eq", "ne", "lt", "le", "gt", "ge",
+}
+
+// Reference é o uso de uma variável ou função em um template.
+type Reference struct {
+	// Name é o nome da função ou o caminho da variável, como "database.host".
+	Name string
+	// Line é a linha do uso; zero quando a referência está no nome do arquivo.
+	Line int
+}
+
+// SyntaxError é um erro de interpretação localizado em um template.
+type SyntaxError struct {
+	Name    string
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Name, e.Line, e.Message)
+}
+
+// Analysis descreve o que um template referencia.
+type Analysis struct {
+	// Path é o caminho relativo do arquivo de origem, separado por "/".
+	Path string
+	// Partial indica que o arquivo é um partial e não gera saída própria.
+	Partial bool
+	// Variables são as chaves de valores lidas no nível raiz ({{ .chave }} ou {{ $.chave }}).
+	Variables []Reference
+	// UnknownFuncs são funções chamadas que não existem no renderer.
+	UnknownFuncs []Reference
+	Err          *SyntaxError
+}
+
+// Collision indica arquivos de origem que geram o mesmo caminho após remover ".tmpl".
+type Collision struct {
+	Target  string
+	Sources []string
+}
+
+// TreeAnalysis é o resultado da análise estática de um template completo.
+type TreeAnalysis struct {
+	Files      []Analysis
+	Collisions []Collision
+}
+
+// AnalyzeTemplate interpreta text com a mesma sintaxe do renderer, sem executá-lo,
+// e coleta variáveis e funções desconhecidas. Erros de sintaxe ficam em Err.
+func AnalyzeTemplate(name, text string) Analysis {
+	return analyzeTemplate(name, text, &contentMode{})
+}
+
+// analyzeTemplate usa os delimitadores e as funções de runtime que mode define
+// para name.
+func analyzeTemplate(name, text string, mode *contentMode) Analysis {
+	result := Analysis{Path: name}
+	left, right := mode.delims(name)
+
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	treeSet := make(map[string]*parse.Tree)
+	if _, err := tree.Parse(text, left, right, treeSet); err != nil {
+		result.Err = syntaxError(name, err)
+		return result
+	}
+
+	known := funcMap()
+	for _, fn := range builtinFuncs {
+		known[fn] = nil
+	}
+	if mode.runtime != nil {
+		for _, fn := range runtimeFuncNames {
+			known[fn] = nil
+		}
+	}
+	a := &analyzer{text: text, known: known, result: &result}
+	for _, t := range treeSet {
+		if t.Root != nil {
+			a.walk(t.Root, true)
+		}
+	}
+	result.Variables = uniqueReferences(result.Variables)
+	result.UnknownFuncs = uniqueReferences(result.UnknownFuncs)
+	return result
+}
+
+// AnalyzeTree analisa todos os arquivos de texto de src e das bases, além dos
+// partials, sem avaliar regras nem valores. Arquivos ignorados (inclusive por
+// .templateignore), copiados sem
+// interpretação e binários sem sufixo ".tmpl" são omitidos, como no renderer.
+func AnalyzeTree(src string, opts RenderOptions) (*TreeAnalysis, error) {
+	mode, err := newContentMode(opts)
+	if err != nil {
+		return nil, err
+	}
+	layers := append(append([]string(nil), opts.Bases...), src)
+	entries, err := overlay(layers)
+	if err != nil {
+		return nil, err
+	}
+	ignore, err := newIgnoreMatcher(layers, opts.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TreeAnalysis{}
+	skipped := make(map[string]bool)
+	targets := make(map[string][]string)
+	for _, entry := range entries {
+		if skipped[pathpkg.Dir(entry.rel)] || ignore.ignored(entry.rel, entry.isDir) {
+			if entry.isDir {
+				skipped[entry.rel] = true
+			}
+			continue
+		}
+		if entry.isDir {
+			if err := ignore.load(entry.rel); err != nil {
+				return nil, err
+			}
+		}
+
+		analysis := Analysis{Path: entry.rel}
+		if base := pathpkg.Base(entry.rel); strings.Contains(base, "{{") {
+			segment := AnalyzeTemplate(entry.rel, base)
+			for i := range segment.Variables {
+				segment.Variables[i].Line = 0
+			}
+			for i := range segment.UnknownFuncs {
+				segment.UnknownFuncs[i].Line = 0
+			}
+			analysis = segment
+		}
+
+		if !entry.isDir {
+			target := strings.TrimSuffix(entry.rel, ".tmpl")
+			targets[target] = append(targets[target], entry.rel)
+
+			data, err := os.ReadFile(entry.path)
+			if err != nil {
+				return nil, fmt.Errorf("read source file: %w", err)
+			}
+			isTemplate := strings.HasSuffix(entry.rel, ".tmpl")
+			if analysis.Err == nil && mode.render(entry.rel, isTemplate) && (isTemplate || !looksBinary(data)) {
+				content := analyzeTemplate(entry.rel, string(data), mode)
+				analysis.Variables = append(analysis.Variables, content.Variables...)
+				analysis.UnknownFuncs = append(analysis.UnknownFuncs, content.UnknownFuncs...)
+				analysis.Err = content.Err
+			}
+		}
+
+		if len(analysis.Variables) > 0 || len(analysis.UnknownFuncs) > 0 || analysis.Err != nil || !entry.isDir {
+			result.Files = append(result.Files, analysis)
+		}
+	}
+
+	for _, dir := range opts.Partials {
+		partials, err := analyzePartials(dir, layerRel(dir, layers), mode)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, partials...)
+	}
+
+	for target, sources := range targets {
+		if len(sources) > 1 {
+			result.Collisions = append(result.Collisions, Collision{Target: target, Sources: sources})
+		}
+	}
+	sort.Slice(result.Collisions, func(i, j int) bool { return result.Collisions[i].Target < result.Collisions[j].Target })
+	return result, nil
+}
+
+func analyzePartials(dir, prefix string, mode *contentMode) ([]Analysis, error) {
+	var result []Analysis
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read partial: %w", err)
+		}
+		name := pathpkg.Join(prefix, filepath.ToSlash(rel))
+		analysis := analyzeTemplate(name, string(data), mode)
+		analysis.Partial = true
+		result = append(result, analysis)
+		return nil
+	})
+	return result, err
+}
+
+type analyzer struct {
+	text   string
+	known  map[string]any
+	result *Analysis
+}
+
+// walk percorre os nós; root indica se "." ainda é o mapa de valores, o que
+// deixa de valer dentro de range e with.
+func (a *analyzer) walk(node parse.Node, root bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			a.walk(child, root)
+		}
+	case *parse.ActionNode:
+		a.walk(n.Pipe, root)
+	case *parse.IfNode:
+		a.walk(n.Pipe, root)
+		a.walk(n.List, root)
+		a.walk(n.ElseList, root)
+	case *parse.RangeNode:
+		a.walk(n.Pipe, root)
+		a.walk(n.List, false)
+		a.walk(n.ElseList, root)
+	case *parse.WithNode:
+		a.walk(n.Pipe, root)
+		a.walk(n.List, false)
+		a.walk(n.ElseList, root)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			a.walk(n.Pipe, root)
+		}
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			a.walk(cmd, root)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			a.walk(arg, root)
+		}
+	case *parse.ChainNode:
+		a.walk(n.Node, root)
+	case *parse.FieldNode:
+		if root && len(n.Ident) > 0 {
+			a.result.Variables = append(a.result.Variables, Reference{Name: strings.Join(n.Ident, "."), Line: a.line(n.Pos)})
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			a.result.Variables = append(a.result.Variables, Reference{Name: strings.Join(n.Ident[1:], "."), Line: a.line(n.Pos)})
+		}
+	case *parse.IdentifierNode:
+		if _, ok := a.known[n.Ident]; !ok {
+			a.result.UnknownFuncs = append(a.result.UnknownFuncs, Reference{Name: n.Ident, Line: a.line(n.Pos)})
+		}
+	}
+}
+
+func (a *analyzer) line(pos parse.Pos) int {
+	offset := int(pos)
+	if offset > len(a.text) {
+		offset = len(a.text)
+	}
+	return 1 + strings.Count(a.text[:offset], "\n")
+}
+
+// syntaxError extrai a linha da mensagem "template: nome:linha: descrição".
+func syntaxError(name string, err error) *SyntaxError {
+	msg := strings.TrimPrefix(err.Error(), "template: ")
+	result := &SyntaxError{Name: name, Message: msg}
+	if rest, ok := strings.CutPrefix(msg, name+":"); ok {
+		if idx := strings.Index(rest, ":"); idx > 0 {
+			if line, convErr := strconv.Atoi(rest[:idx]); convErr == nil {
+				result.Line = line
+				result.Message = strings.TrimSpace(rest[idx+1:])
+			}
+		}
+	}
+	return result
+}
+
+// uniqueReferences ordena por linha e remove repetições do mesmo nome na mesma linha.
+func uniqueReferences(refs []Reference) []Reference {
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].Line < refs[j].Line })
+	seen := make(map[Reference]bool, len(refs))
+	result := refs[:0]
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			result = append(result, ref)
+		}
+	}
+	return result
+}
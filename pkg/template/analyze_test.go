@@ -0,0 +1,72 @@
+package template
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeTemplateReferences(t *testing.T) {
+	t.Parallel()
+
+	text := "{{ .name | toUpper }}\n" +
+		"{{ if .enabled }}{{ $.name }}{{ end }}\n" +
+		"{{ range .items }}{{ .inner }}{{ end }}\n" +
+		"{{ shout .name }} {{ shout .name }}\n"
+
+	analysis := AnalyzeTemplate("main.go.tmpl", text)
+	require.Nil(t, analysis.Err)
+	require.Equal(t, []Reference{
+		{Name: "name", Line: 1},
+		{Name: "enabled", Line: 2},
+		{Name: "name", Line: 2},
+		{Name: "items", Line: 3},
+		{Name: "name", Line: 4},
+	}, analysis.Variables)
+	require.Equal(t, []Reference{{Name: "shout", Line: 4}}, analysis.UnknownFuncs)
+}
+
+func TestAnalyzeTemplateSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	analysis := AnalyzeTemplate("main.go", "package main\n\n{{ end }}\n")
+	require.NotNil(t, analysis.Err)
+	require.Equal(t, "main.go", analysis.Err.Name)
+	require.Equal(t, 3, analysis.Err.Line)
+	require.Contains(t, analysis.Err.Message, "unexpected {{end}}")
+}
+
+func TestAnalyzeTreeCollisionsAndPartials(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writeFiles(t, src, map[string]string{
+		"main.go":               "package main\n",
+		"main.go.tmpl":          "package {{ .pkg }}\n",
+		"{{ .service }}/app.go": "package app\n",
+		"_partials/header.tmpl": "// {{ .owner }}\n",
+		"bin/tool":              "\x00\x01{{ .ignored }}",
+		"template.yaml":         "name: demo\n",
+	})
+
+	analysis, err := AnalyzeTree(src, RenderOptions{
+		Ignore:   []string{"/template.yaml", "/_partials/"},
+		Partials: []string{filepath.Join(src, "_partials")},
+	})
+	require.NoError(t, err)
+
+	variables := map[string][]Reference{}
+	for _, file := range analysis.Files {
+		require.Nil(t, file.Err)
+		if len(file.Variables) > 0 {
+			variables[file.Path] = file.Variables
+		}
+	}
+	require.Equal(t, map[string][]Reference{
+		"main.go.tmpl":          {{Name: "pkg", Line: 1}},
+		"{{ .service }}":        {{Name: "service", Line: 0}},
+		"_partials/header.tmpl": {{Name: "owner", Line: 1}},
+	}, variables)
+	require.Equal(t, []Collision{{Target: "main.go", Sources: []string{"main.go", "main.go.tmpl"}}}, analysis.Collisions)
+}
@@ -0,0 +1,334 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// Condition é uma expressão booleana avaliada sobre os valores do template.
+//
+// A gramática aceita identificadores (opcionalmente prefixados com "."),
+// literais entre aspas, palavras simples (true, false, números), os operadores
+// == e !=, a negação !, && e || e parênteses. Em comparações, uma palavra sem
+// aspas à direita do operador é tratada como literal (env == prod), a menos
+// que seja prefixada com "." (env == .default_env). Um identificador isolado
+// é verdadeiro quando seu valor não é vazio nem um booleano falso.
+type Condition struct {
+	expr string
+	root conditionNode
+}
+
+// ParseCondition compila uma expressão de condição.
+func ParseCondition(expr string) (*Condition, error) {
+	tokens, err := tokenizeCondition(expr)
+	if err != nil {
+		return nil, fmt.Errorf("condição %q: %w", expr, err)
+	}
+	p := &conditionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("condição %q: %w", expr, err)
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("condição %q: token inesperado %q", expr, p.peek().text)
+	}
+	return &Condition{expr: expr, root: root}, nil
+}
+
+// Eval avalia a condição com os valores informados.
+func (c *Condition) Eval(values map[string]any) bool {
+	return c.root.truth(values)
+}
+
+// Identifiers retorna as variáveis referenciadas pela condição.
+func (c *Condition) Identifiers() []string {
+	var ids []string
+	c.root.collect(&ids)
+	return ids
+}
+
+func (c *Condition) String() string {
+	return c.expr
+}
+
+type conditionNode interface {
+	truth(values map[string]any) bool
+	value(values map[string]any) string
+	collect(ids *[]string)
+}
+
+type identNode struct{ name string }
+
+func (n identNode) value(values map[string]any) string {
+	value, _ := valuespkg.Lookup(values, n.name)
+	return valuespkg.String(value)
+}
+
+func (n identNode) truth(values map[string]any) bool {
+	value, _ := valuespkg.Lookup(values, n.name)
+	if !valuespkg.IsScalar(value) {
+		return !isEmptyCollection(value)
+	}
+	return truthy(valuespkg.String(value))
+}
+
+func (n identNode) collect(ids *[]string) { *ids = append(*ids, n.name) }
+
+type literalNode struct{ text string }
+
+func (n literalNode) value(map[string]any) string { return n.text }
+func (n literalNode) truth(map[string]any) bool   { return truthy(n.text) }
+func (n literalNode) collect(*[]string)           {}
+
+type notNode struct{ inner conditionNode }
+
+func (n notNode) truth(values map[string]any) bool   { return !n.inner.truth(values) }
+func (n notNode) value(values map[string]any) string { return strconv.FormatBool(n.truth(values)) }
+func (n notNode) collect(ids *[]string)              { n.inner.collect(ids) }
+
+type binaryNode struct {
+	op          string
+	left, right conditionNode
+}
+
+func (n binaryNode) truth(values map[string]any) bool {
+	switch n.op {
+	case "&&":
+		return n.left.truth(values) && n.right.truth(values)
+	case "||":
+		return n.left.truth(values) || n.right.truth(values)
+	case "==":
+		return equalValues(n.left.value(values), n.right.value(values))
+	default:
+		return !equalValues(n.left.value(values), n.right.value(values))
+	}
+}
+
+func (n binaryNode) value(values map[string]any) string {
+	return strconv.FormatBool(n.truth(values))
+}
+
+func (n binaryNode) collect(ids *[]string) {
+	n.left.collect(ids)
+	n.right.collect(ids)
+}
+
+func isEmptyCollection(value any) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func truthy(value string) bool {
+	if value == "" {
+		return false
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	return true
+}
+
+func equalValues(a, b string) bool {
+	ba, errA := strconv.ParseBool(a)
+	bb, errB := strconv.ParseBool(b)
+	if errA == nil && errB == nil {
+		return ba == bb
+	}
+	return a == b
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOp
+)
+
+type conditionToken struct {
+	kind tokenKind
+	text string
+}
+
+func tokenizeCondition(expr string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, conditionToken{kind: tokenOp, text: string(r)})
+			i++
+		case r == '&' || r == '|' || r == '=':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("operador inválido %q", string(r))
+			}
+			tokens = append(tokens, conditionToken{kind: tokenOp, text: string([]rune{r, r})})
+			i += 2
+		case r == '!':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, conditionToken{kind: tokenOp, text: "!="})
+				i += 2
+				continue
+			}
+			tokens = append(tokens, conditionToken{kind: tokenOp, text: "!"})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("literal sem aspas de fechamento")
+			}
+			tokens = append(tokens, conditionToken{kind: tokenString, text: string(runes[i+1 : end])})
+			i = end + 1
+		case isWordRune(r):
+			end := i
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, conditionToken{kind: tokenWord, text: string(runes[i:end])})
+			i = end
+		default:
+			return nil, fmt.Errorf("caractere inesperado %q", string(r))
+		}
+	}
+	return tokens, nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
+
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+}
+
+func (p *conditionParser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *conditionParser) peek() conditionToken {
+	if p.done() {
+		return conditionToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *conditionParser) acceptOp(op string) bool {
+	if !p.done() && p.tokens[p.pos].kind == tokenOp && p.tokens[p.pos].text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *conditionParser) parseOr() (conditionNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (conditionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (conditionNode, error) {
+	if p.acceptOp("!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner: inner}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *conditionParser) parseComparison() (conditionNode, error) {
+	if p.acceptOp("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptOp(")") {
+			return nil, fmt.Errorf("parêntese não fechado")
+		}
+		return inner, nil
+	}
+
+	left, err := p.parseOperand(false)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.acceptOp(op) {
+			right, err := p.parseOperand(true)
+			if err != nil {
+				return nil, err
+			}
+			return binaryNode{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseOperand(literalWords bool) (conditionNode, error) {
+	if p.done() {
+		return nil, fmt.Errorf("expressão incompleta")
+	}
+	tok := p.tokens[p.pos]
+	switch tok.kind {
+	case tokenString:
+		p.pos++
+		return literalNode{text: tok.text}, nil
+	case tokenWord:
+		p.pos++
+		if (literalWords && !strings.HasPrefix(tok.text, ".")) || isLiteralWord(tok.text) {
+			return literalNode{text: tok.text}, nil
+		}
+		return identNode{name: strings.TrimPrefix(tok.text, ".")}, nil
+	default:
+		return nil, fmt.Errorf("token inesperado %q", tok.text)
+	}
+}
+
+func isLiteralWord(word string) bool {
+	if word == "true" || word == "false" {
+		return true
+	}
+	if _, err := strconv.ParseFloat(word, 64); err == nil {
+		return true
+	}
+	return false
+}
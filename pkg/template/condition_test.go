@@ -0,0 +1,82 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionEval(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]any{
+		"enable_grpc": "false",
+		"enable_nats": "true",
+		"env":         "prod",
+		"name":        "svc",
+	}
+
+	cases := map[string]bool{
+		"enable_grpc == false":                   true,
+		"enable_grpc":                            false,
+		"!enable_grpc":                           true,
+		".enable_nats":                           true,
+		"enable_nats && env == prod":             true,
+		"enable_grpc || env != 'prod'":           false,
+		"(enable_grpc || enable_nats) && name":   true,
+		`env == "dev" || env == "prod"`:          true,
+		"missing":                                false,
+		"missing == ''":                          true,
+		"enable_nats == true && !(env == 'dev')": true,
+		"name == .name":                          true,
+		"name == name":                           false,
+	}
+
+	for expr, expected := range cases {
+		cond, err := ParseCondition(expr)
+		require.NoError(t, err, expr)
+		require.Equal(t, expected, cond.Eval(values), expr)
+	}
+}
+
+func TestConditionIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	cond, err := ParseCondition("enable_grpc == false && (env == 'prod' || .region)")
+	require.NoError(t, err)
+	require.Equal(t, []string{"enable_grpc", "env", "region"}, cond.Identifiers())
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"", "a ==", "a = b", "(a", "a b", "'open", "a & b", "a > b"} {
+		_, err := ParseCondition(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+func TestConditionEvalNested(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]any{
+		"database": map[string]any{"driver": "postgres", "tls": true},
+		"services": []any{},
+		"subjects": []any{"orders.created"},
+	}
+
+	cases := map[string]bool{
+		"database.driver == postgres": true,
+		".database.tls":               true,
+		"database.missing":            false,
+		"services":                    false,
+		"subjects && database.tls":    true,
+		"database.driver != 'mysql'":  true,
+	}
+
+	for expr, expected := range cases {
+		cond, err := ParseCondition(expr)
+		require.NoError(t, err, expr)
+		require.Equal(t, expected, cond.Eval(values), expr)
+	}
+}
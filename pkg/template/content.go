@@ -0,0 +1,92 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Delimitadores padrão de text/template.
+const (
+	DefaultLeftDelim  = "{{"
+	DefaultRightDelim = "}}"
+)
+
+// DelimiterRule troca os delimitadores de ação dos arquivos cujo caminho de
+// origem casa com algum padrão. Uma regra sem padrões vale para o template inteiro.
+type DelimiterRule struct {
+	Left     string
+	Right    string
+	Patterns []string
+}
+
+// contentMode decide, pelo caminho de origem, se um arquivo é interpretado como
+// template, com quais delimitadores e com quais funções de runtime.
+type contentMode struct {
+	delimiters []DelimiterRule
+	copyOnly   []string
+	renderOnly []string
+	runtime    *Runtime
+}
+
+func newContentMode(opts RenderOptions) (*contentMode, error) {
+	for _, rule := range opts.Delimiters {
+		if rule.Left == "" || rule.Right == "" {
+			return nil, errors.New("delimiters require both left and right")
+		}
+		if rule.Left == rule.Right {
+			return nil, fmt.Errorf("left and right delimiters must differ: %s", rule.Left)
+		}
+		if err := validGlobs(rule.Patterns); err != nil {
+			return nil, err
+		}
+	}
+	if err := validGlobs(opts.CopyOnly); err != nil {
+		return nil, err
+	}
+	if err := validGlobs(opts.RenderOnly); err != nil {
+		return nil, err
+	}
+	return &contentMode{delimiters: opts.Delimiters, copyOnly: opts.CopyOnly, renderOnly: opts.RenderOnly, runtime: opts.Runtime}, nil
+}
+
+// render informa se o conteúdo de rel deve ser interpretado. copy_only tem
+// precedência; arquivos ".tmpl" são sempre interpretados; com render_only, os
+// demais arquivos são copiados.
+func (m *contentMode) render(rel string, isTemplate bool) bool {
+	if matchAny(m.copyOnly, rel) {
+		return false
+	}
+	if isTemplate || len(m.renderOnly) == 0 {
+		return true
+	}
+	return matchAny(m.renderOnly, rel)
+}
+
+// delims retorna os delimitadores da última regra que se aplica a rel.
+func (m *contentMode) delims(rel string) (string, string) {
+	left, right := DefaultLeftDelim, DefaultRightDelim
+	for _, rule := range m.delimiters {
+		if len(rule.Patterns) == 0 || matchAny(rule.Patterns, rel) {
+			left, right = rule.Left, rule.Right
+		}
+	}
+	return left, right
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func validGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		if !ValidGlob(pattern) {
+			return fmt.Errorf("invalid pattern: %s", pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package template
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTreeDelimitersAndCopyModes(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writeFiles(t, src, map[string]string{
+		"main.go":                   "package [[ .pkg ]]\n\nconst tmpl = `{{ .Name }}`\n",
+		"README.md":                 "# {{ .pkg }}\n",
+		"charts/values.yaml":        "image: {{ .Values.image }}\n",
+		"charts/Chart.yaml.tmpl":    "name: {{ .pkg }}\n",
+		"_partials/header.go.tmpl":  "// [[ .pkg ]]\n",
+		"cmd/{{ .pkg }}/doc.go":     "[[ template \"header\" . ]]package main\n",
+		".github/workflows/ci.yaml": "run: ${{ matrix.go }}\n",
+	})
+
+	tree, err := BuildTree(context.Background(), src, map[string]any{"pkg": "demo"}, RenderOptions{
+		Ignore:     []string{"/_partials/"},
+		Partials:   []string{filepath.Join(src, "_partials")},
+		Delimiters: []DelimiterRule{{Left: "[[", Right: "]]", Patterns: []string{"**/*.go", "_partials/**"}}},
+		CopyOnly:   []string{"charts/**", ".github/**"},
+	})
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for i := range tree.Files {
+		content, err := tree.Files[i].Bytes()
+		require.NoError(t, err)
+		files[tree.Files[i].Path] = string(content)
+	}
+	require.Equal(t, map[string]string{
+		"main.go":                   "package demo\n\nconst tmpl = `{{ .Name }}`\n",
+		"README.md":                 "# demo\n",
+		"charts/values.yaml":        "image: {{ .Values.image }}\n",
+		"charts/Chart.yaml":         "name: {{ .pkg }}\n",
+		"cmd/demo/doc.go":           "// demo\npackage main\n",
+		".github/workflows/ci.yaml": "run: ${{ matrix.go }}\n",
+	}, files)
+}
+
+func TestBuildTreeRenderOnly(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writeFiles(t, src, map[string]string{
+		"config.yaml":    "name: {{ .pkg }}\n",
+		"notes.md":       "{{ raw }}\n",
+		"README.md.tmpl": "# {{ .pkg }}\n",
+	})
+
+	tree, err := BuildTree(context.Background(), src, map[string]any{"pkg": "demo"}, RenderOptions{
+		RenderOnly: []string{"*.yaml"},
+	})
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for i := range tree.Files {
+		content, err := tree.Files[i].Bytes()
+		require.NoError(t, err)
+		files[tree.Files[i].Path] = string(content)
+	}
+	require.Equal(t, map[string]string{
+		"config.yaml": "name: demo\n",
+		"notes.md":    "{{ raw }}\n",
+		"README.md":   "# demo\n",
+	}, files)
+}
+
+func TestContentModeValidation(t *testing.T) {
+	t.Parallel()
+
+	_, err := newContentMode(RenderOptions{Delimiters: []DelimiterRule{{Left: "[["}}})
+	require.Error(t, err)
+	_, err = newContentMode(RenderOptions{Delimiters: []DelimiterRule{{Left: "%%", Right: "%%"}}})
+	require.Error(t, err)
+	_, err = newContentMode(RenderOptions{CopyOnly: []string{"["}})
+	require.Error(t, err)
+
+	mode, err := newContentMode(RenderOptions{Delimiters: []DelimiterRule{
+		{Left: "[[", Right: "]]"},
+		{Left: "<%", Right: "%>", Patterns: []string{"web/**"}},
+	}})
+	require.NoError(t, err)
+	left, right := mode.delims("main.go")
+	require.Equal(t, []string{"[[", "]]"}, []string{left, right})
+	left, right = mode.delims("web/index.html")
+	require.Equal(t, []string{"<%", "%>"}, []string{left, right})
+}
@@ -0,0 +1,320 @@
+package template
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"hash/fnv"
+	"math/rand"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vertikon/mcp-ultra-templates/pkg/semver"
+	valuespkg "github.com/vertikon/mcp-ultra-templates/pkg/values"
+)
+
+// libraryFuncs são as funções determinísticas disponíveis em todo template. A
+// ordem dos argumentos favorece pipelines: o valor processado vem por último.
+func libraryFuncs() template.FuncMap {
+	return template.FuncMap{
+		"default":       defaultValue,
+		"required":      required,
+		"coalesce":      coalesce,
+		"ternary":       ternary,
+		"replace":       replace,
+		"trim":          trim,
+		"trimPrefix":    trimPrefix,
+		"trimSuffix":    trimSuffix,
+		"indent":        indent,
+		"nindent":       nindent,
+		"toYaml":        toYaml,
+		"toJson":        toJSON,
+		"fromJson":      fromJSON,
+		"list":          list,
+		"dict":          dict,
+		"has":           has,
+		"join":          join,
+		"split":         split,
+		"sha256":        sha256Sum,
+		"base64":        base64Encode,
+		"base64Decode":  base64Decode,
+		"plural":        plural,
+		"semver":        semver.Parse,
+		"semverCompare": semverCompare,
+		"goIdent":       goIdent,
+	}
+}
+
+// runtimeFuncNames são as funções não determinísticas habilitadas por Runtime.
+var runtimeFuncNames = []string{"now", "uuid"}
+
+// Runtime habilita as funções now e uuid. Now é o instante retornado por now;
+// Seed determina a sequência de uuid, que é independente por arquivo de origem.
+// Com os mesmos Now e Seed, a saída é reproduzível.
+type Runtime struct {
+	Now  time.Time
+	Seed int64
+}
+
+// funcs retorna now e uuid para o arquivo source; nil quando r é nil.
+func (r *Runtime) funcs(source string) template.FuncMap {
+	if r == nil {
+		return nil
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(source))
+	rng := rand.New(rand.NewSource(r.Seed ^ int64(h.Sum64()))) //nolint:gosec // reprodutibilidade, não segurança
+	return template.FuncMap{
+		"now": func() time.Time { return r.Now },
+		"uuid": func() string {
+			var b [16]byte
+			_, _ = rng.Read(b[:])
+			b[6] = (b[6] & 0x0f) | 0x40
+			b[8] = (b[8] & 0x3f) | 0x80
+			return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+		},
+	}
+}
+
+// empty segue a noção de vazio de text/template: zero, nil e coleções vazias.
+func empty(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+func defaultValue(fallback, value any) any {
+	if empty(value) {
+		return fallback
+	}
+	return value
+}
+
+func required(message string, value any) (any, error) {
+	if empty(value) {
+		return nil, errors.New(message)
+	}
+	return value, nil
+}
+
+func coalesce(values ...any) any {
+	for _, value := range values {
+		if !empty(value) {
+			return value
+		}
+	}
+	return nil
+}
+
+func ternary(whenTrue, whenFalse any, condition bool) any {
+	if condition {
+		return whenTrue
+	}
+	return whenFalse
+}
+
+func replace(old, replacement string, value any) string {
+	return strings.ReplaceAll(valuespkg.String(value), old, replacement)
+}
+
+func trim(value any) string {
+	return strings.TrimSpace(valuespkg.String(value))
+}
+
+func trimPrefix(prefix string, value any) string {
+	return strings.TrimPrefix(valuespkg.String(value), prefix)
+}
+
+func trimSuffix(suffix string, value any) string {
+	return strings.TrimSuffix(valuespkg.String(value), suffix)
+}
+
+// indent prefixa cada linha não vazia com n espaços.
+func indent(n int, value any) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(valuespkg.String(value), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nindent é indent precedido de quebra de linha, para uso após uma chave YAML.
+func nindent(n int, value any) string {
+	return "\n" + indent(n, value)
+}
+
+// toYaml serializa value sem a quebra de linha final.
+func toYaml(value any) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("toYaml: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("toYaml: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func toJSON(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("toJson: %w", err)
+	}
+	return string(data), nil
+}
+
+func fromJSON(value any) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(valuespkg.String(value)))
+	decoder.UseNumber()
+	var result any
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("fromJson: %w", err)
+	}
+	return result, nil
+}
+
+func list(items ...any) []any {
+	return append([]any{}, items...)
+}
+
+// dict monta um mapa a partir de pares chave, valor.
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict: expected key/value pairs")
+	}
+	result := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict: key %v is not a string", pairs[i])
+		}
+		result[key] = pairs[i+1]
+	}
+	return result, nil
+}
+
+// has informa se needle é elemento da lista ou chave do mapa.
+func has(needle, collection any) bool {
+	v := reflect.ValueOf(collection)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if reflect.DeepEqual(v.Index(i).Interface(), needle) {
+				return true
+			}
+		}
+	case reflect.Map:
+		key := reflect.ValueOf(needle)
+		if key.IsValid() && key.Type().AssignableTo(v.Type().Key()) {
+			return v.MapIndex(key).IsValid()
+		}
+	}
+	return false
+}
+
+func join(sep string, items any) string {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return valuespkg.String(items)
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = valuespkg.String(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+func split(sep string, value any) []string {
+	return strings.Split(valuespkg.String(value), sep)
+}
+
+func sha256Sum(value any) string {
+	sum := sha256.Sum256([]byte(valuespkg.String(value)))
+	return hex.EncodeToString(sum[:])
+}
+
+func base64Encode(value any) string {
+	return base64.StdEncoding.EncodeToString([]byte(valuespkg.String(value)))
+}
+
+func base64Decode(value any) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(valuespkg.String(value))
+	if err != nil {
+		return "", fmt.Errorf("base64Decode: %w", err)
+	}
+	return string(data), nil
+}
+
+// plural aplica as regras regulares de plural do inglês, usadas em nomes de
+// código: "service" vira "services", "policy" vira "policies" e "box", "boxes".
+func plural(value any) string {
+	word := valuespkg.String(value)
+	lower := strings.ToLower(word)
+	switch {
+	case word == "":
+		return word
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return word + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return word[:len(word)-1] + "ies"
+	}
+	return word + "s"
+}
+
+// semverCompare informa se version satisfaz constraint, como ">=1.21 <2".
+func semverCompare(constraint string, version any) (bool, error) {
+	return semver.Satisfies(constraint, valuespkg.String(version))
+}
+
+// goIdent converte value em um identificador Go válido: caracteres inválidos
+// viram "_", um dígito inicial recebe o prefixo "_" e palavras reservadas
+// recebem o sufixo "_".
+func goIdent(value any) string {
+	var buf strings.Builder
+	pending := false
+	for _, r := range valuespkg.String(value) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			pending = buf.Len() > 0
+			continue
+		}
+		if pending && !strings.HasSuffix(buf.String(), "_") && r != '_' {
+			buf.WriteRune('_')
+		}
+		pending = false
+		buf.WriteRune(r)
+	}
+	ident := buf.String()
+	switch {
+	case ident == "":
+		return "_"
+	case unicode.IsDigit([]rune(ident)[0]):
+		ident = "_" + ident
+	case token.IsKeyword(ident):
+		ident += "_"
+	}
+	return ident
+}
@@ -0,0 +1,130 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLibraryFuncs(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]any{
+		"name":     "Order Service",
+		"empty":    "",
+		"zero":     0,
+		"enabled":  true,
+		"subjects": []any{"orders.created", "orders.paid"},
+		"labels":   map[string]any{"team": "core", "tier": 1},
+		"version":  "1.22.3",
+	}
+
+	cases := map[string]string{
+		`{{ .empty | default "fallback" }}`:                                                  "fallback",
+		`{{ .name | default "fallback" }}`:                                                   "Order Service",
+		`{{ .zero | default 8080 }}`:                                                         "8080",
+		`{{ required "name is required" .name }}`:                                            "Order Service",
+		`{{ coalesce .empty .zero "third" }}`:                                                "third",
+		`{{ ternary "on" "off" .enabled }}`:                                                  "on",
+		`{{ .name | replace " " "_" }}`:                                                      "Order_Service",
+		`{{ trim "  x  " }}|{{ trimPrefix "v" "v1.2" }}|{{ trimSuffix ".go" "main.go" }}`:    "x|1.2|main",
+		"a:{{ \"b: 1\\nc: 2\" | nindent 2 }}":                                                "a:\n  b: 1\n  c: 2",
+		`{{ "x\n\ny" | indent 4 }}`:                                                          "    x\n\n    y",
+		`{{ toYaml .labels }}`:                                                               "team: core\ntier: 1",
+		`{{ toJson .subjects }}`:                                                             `["orders.created","orders.paid"]`,
+		`{{ (fromJson "{\"a\":[1,2]}").a | join "+" }}`:                                      "1+2",
+		`{{ list 1 "a" true | toJson }}`:                                                     `[1,"a",true]`,
+		`{{ dict "k" "v" "n" 2 | toJson }}`:                                                  `{"k":"v","n":2}`,
+		`{{ has "orders.paid" .subjects }} {{ has "team" .labels }} {{ has "x" .labels }}`:   "true true false",
+		`{{ .subjects | join ", " }}`:                                                        "orders.created, orders.paid",
+		`{{ range split "." "a.b.c" }}[{{ . }}]{{ end }}`:                                    "[a][b][c]",
+		`{{ sha256 "abc" }}`:                                                                 "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		`{{ base64 "hello" }} {{ base64Decode "aGVsbG8=" }}`:                                 "aGVsbG8= hello",
+		`{{ plural "service" }} {{ plural "policy" }} {{ plural "box" }} {{ plural "key" }}`: "services policies boxes keys",
+		`{{ semverCompare ">=1.21 <2" .version }} {{ semverCompare "^1.23" .version }}`:      "true false",
+		`{{ (semver .version).Minor }}`:                                                      "22",
+		`{{ goIdent .name }} {{ goIdent "9lives" }} {{ goIdent "type" }} {{ goIdent "my__var" }} {{ goIdent "-" }}`: "Order_Service _9lives type_ my__var _",
+	}
+
+	for text, expected := range cases {
+		rendered, err := RenderString("case", text, values)
+		require.NoError(t, err, text)
+		require.Equal(t, expected, rendered, text)
+	}
+}
+
+func TestLibraryFuncErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, text := range []string{
+		`{{ required "name is required" .empty }}`,
+		`{{ dict "k" }}`,
+		`{{ dict 1 2 }}`,
+		`{{ fromJson "{" }}`,
+		`{{ base64Decode "%%" }}`,
+		`{{ semverCompare ">=x" "1.0.0" }}`,
+		`{{ now }}`,
+	} {
+		_, err := RenderString("case", text, map[string]any{"empty": ""})
+		require.Error(t, err, text)
+	}
+
+	_, err := RenderString("case", `{{ required "name is required" .empty }}`, map[string]any{"empty": ""})
+	require.ErrorContains(t, err, "name is required")
+}
+
+func TestRuntimeFuncsAreReproducible(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writeFiles(t, src, map[string]string{
+		"a.txt":                "{{ now.Format \"2006-01-02\" }} {{ uuid }} {{ uuid }}\n",
+		"b.txt":                "{{ uuid }} {{ template \"stamp\" }}\n",
+		"_partials/stamp.tmpl": `{{ define "stamp" }}{{ now.Year }}{{ end }}`,
+	})
+
+	render := func(seed int64) map[string]string {
+		tree, err := BuildTree(context.Background(), src, nil, RenderOptions{
+			Ignore:   []string{"/_partials/"},
+			Partials: []string{filepath.Join(src, "_partials")},
+			Runtime:  &Runtime{Now: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC), Seed: seed},
+		})
+		require.NoError(t, err)
+		files := map[string]string{}
+		for _, file := range tree.Files {
+			files[file.Path] = string(file.Content)
+		}
+		return files
+	}
+
+	first := render(42)
+	require.Equal(t, first, render(42))
+	require.NotEqual(t, first, render(43))
+
+	uuid := regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}`)
+	require.Regexp(t, `^2025-01-10 `+uuid.String()+` `+uuid.String()+"\n$", first["a.txt"])
+	require.Regexp(t, `^`+uuid.String()+" 2025\n$", first["b.txt"])
+
+	_, err := BuildTree(context.Background(), src, nil, RenderOptions{Ignore: []string{"/_partials/", "/b.txt"}})
+	require.ErrorContains(t, err, `function "now" not defined`)
+}
+
+func TestAnalyzeRuntimeFuncs(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "id.txt"), []byte("{{ uuid }}\n"), 0o644))
+
+	analysis, err := AnalyzeTree(src, RenderOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []Reference{{Name: "uuid", Line: 1}}, analysis.Files[0].UnknownFuncs)
+
+	analysis, err = AnalyzeTree(src, RenderOptions{Runtime: &Runtime{}})
+	require.NoError(t, err)
+	require.Empty(t, analysis.Files[0].UnknownFuncs)
+}
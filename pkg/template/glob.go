@@ -0,0 +1,61 @@
+package template
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob verifica se o caminho relativo (separado por "/") corresponde ao padrão.
+// Além da sintaxe de path.Match, o segmento "**" corresponde a zero ou mais diretórios.
+func MatchGlob(pattern, name string) bool {
+	pattern = strings.Trim(pattern, "/")
+	name = strings.Trim(name, "/")
+	if pattern == "" {
+		return name == ""
+	}
+	return matchSegments(strings.Split(pattern, "/"), splitPath(name))
+}
+
+// ValidGlob informa se o padrão é sintaticamente válido.
+func ValidGlob(pattern string) bool {
+	for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
+
+func splitPath(name string) []string {
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, "/")
+}
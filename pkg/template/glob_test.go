@@ -0,0 +1,39 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"internal/grpc/**", "internal/grpc", true},
+		{"internal/grpc/**", "internal/grpc/server/server.go", true},
+		{"internal/grpc/**", "internal/grpcx/file.go", false},
+		{"**/*.pb.go", "api/v1/service.pb.go", true},
+		{"**/*.pb.go", "service.pb.go", true},
+		{"*.md", "docs/README.md", false},
+		{"docs/*.md", "docs/README.md", true},
+		{"/docs/**", "docs/a/b.txt", true},
+		{"cmd/*/main.go", "cmd/server/main.go", true},
+		{"**", "anything/at/all", true},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.match, MatchGlob(tc.pattern, tc.name), "%s ~ %s", tc.pattern, tc.name)
+	}
+}
+
+func TestValidGlob(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, ValidGlob("internal/**/*.go"))
+	require.False(t, ValidGlob("internal/[a"))
+}
@@ -0,0 +1,186 @@
+package template
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Transform altera o conteúdo renderizado de um arquivo de texto antes da escrita.
+// rel é o caminho de destino relativo, separado por "/".
+type Transform func(rel string, content []byte) ([]byte, error)
+
+// ModulePath retorna o caminho declarado na diretiva module de um go.mod,
+// ou vazio quando não houver diretiva.
+func ModulePath(gomod []byte) string {
+	for _, line := range strings.Split(string(gomod), "\n") {
+		fields := strings.Fields(stripGoModComment(line))
+		if len(fields) == 2 && fields[0] == "module" {
+			if unquoted, err := strconv.Unquote(fields[1]); err == nil {
+				return unquoted
+			}
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// GoModuleTransform reescreve referências ao módulo from para to: as diretivas
+// module, require, replace e exclude de qualquer go.mod e os imports de arquivos .go.
+// Caminhos são reescritos quando iguais a from ou prefixados por from + "/".
+func GoModuleTransform(from, to string) Transform {
+	return GoModulesTransform([]string{from}, to)
+}
+
+// GoModulesTransform é GoModuleTransform para vários módulos de origem, como as
+// camadas de um template herdado. Cada caminho é reescrito uma única vez, pelo
+// módulo mais longo que o prefixa; caminhos que já estão sob to são mantidos,
+// mesmo quando um dos módulos de origem prefixa to.
+func GoModulesTransform(from []string, to string) Transform {
+	r := &moduleRewrite{to: to}
+	for _, module := range from {
+		if module != "" && module != to {
+			r.from = append(r.from, module)
+		}
+	}
+	if to != "" && len(r.from) > 0 {
+		r.from = append(r.from, to)
+	}
+	sort.SliceStable(r.from, func(i, j int) bool { return len(r.from[i]) > len(r.from[j]) })
+
+	return func(rel string, content []byte) ([]byte, error) {
+		if to == "" || len(r.from) == 0 {
+			return content, nil
+		}
+		switch {
+		case path.Base(rel) == "go.mod":
+			return rewriteGoMod(content, r), nil
+		case strings.HasSuffix(rel, ".go"):
+			return rewriteGoImports(content, r), nil
+		default:
+			return content, nil
+		}
+	}
+}
+
+// moduleRewrite mapeia os módulos de from, do mais longo ao mais curto, para to.
+type moduleRewrite struct {
+	from []string
+	to   string
+}
+
+func (r *moduleRewrite) path(p string) (string, bool) {
+	for _, from := range r.from {
+		if p == from {
+			return r.to, from != r.to
+		}
+		if strings.HasPrefix(p, from+"/") {
+			return r.to + strings.TrimPrefix(p, from), from != r.to
+		}
+	}
+	return p, false
+}
+
+func rewriteGoMod(content []byte, r *moduleRewrite) []byte {
+	lines := strings.SplitAfter(string(content), "\n")
+	for i, line := range lines {
+		body := stripGoModComment(line)
+		rest := line[len(body):]
+
+		var out strings.Builder
+		changed := false
+		for pos := 0; pos < len(body); {
+			if isGoModSpace(body[pos]) {
+				out.WriteByte(body[pos])
+				pos++
+				continue
+			}
+			end := pos
+			for end < len(body) && !isGoModSpace(body[end]) {
+				end++
+			}
+			tok := body[pos:end]
+			if rewritten, ok := rewriteGoModToken(tok, r); ok {
+				tok = rewritten
+				changed = true
+			}
+			out.WriteString(tok)
+			pos = end
+		}
+		if changed {
+			lines[i] = out.String() + rest
+		}
+	}
+	return []byte(strings.Join(lines, ""))
+}
+
+func rewriteGoModToken(tok string, r *moduleRewrite) (string, bool) {
+	if unquoted, err := strconv.Unquote(tok); err == nil {
+		if rewritten, ok := r.path(unquoted); ok {
+			return strconv.Quote(rewritten), true
+		}
+		return tok, false
+	}
+	return r.path(tok)
+}
+
+func isGoModSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+func stripGoModComment(line string) string {
+	if idx := strings.Index(line, "//"); idx >= 0 {
+		return line[:idx]
+	}
+	return strings.TrimRight(line, "\n")
+}
+
+// rewriteGoImports substitui apenas os literais dos imports, preservando o
+// restante do arquivo byte a byte. Somente a cláusula package e os imports
+// precisam ser válidos; caso contrário o arquivo fica inalterado.
+func rewriteGoImports(content []byte, r *moduleRewrite) []byte {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ImportsOnly)
+	if err != nil {
+		return content
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	for _, spec := range file.Imports {
+		value, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		rewritten, ok := r.path(value)
+		if !ok {
+			continue
+		}
+		edits = append(edits, edit{
+			start: fset.Position(spec.Path.Pos()).Offset,
+			end:   fset.Position(spec.Path.End()).Offset,
+			text:  strconv.Quote(rewritten),
+		})
+	}
+	if len(edits) == 0 {
+		return content
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var buf bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		buf.Write(content[last:e.start])
+		buf.WriteString(e.text)
+		last = e.end
+	}
+	buf.Write(content[last:])
+	return buf.Bytes()
+}
@@ -0,0 +1,144 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sourceModule = "github.com/vertikon/mcp-ultra-wasm-wasm/mcp/mcp-ultra-wasm-wasm"
+
+func TestModulePath(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, sourceModule, ModulePath([]byte("// comment\nmodule "+sourceModule+"\n\ngo 1.24.0\n")))
+	require.Equal(t, "example.com/quoted", ModulePath([]byte(`module "example.com/quoted"`)))
+	require.Empty(t, ModulePath([]byte("go 1.24.0\n")))
+}
+
+func TestGoModuleTransformGoMod(t *testing.T) {
+	t.Parallel()
+
+	input := `module ` + sourceModule + ` // main module
+
+go 1.24.0
+
+require (
+	github.com/stretchr/testify v1.11.1
+	` + sourceModule + `/sdk v0.1.0
+	` + sourceModule + `x v0.1.0
+)
+
+replace ` + sourceModule + `/sdk => ./sdk
+
+replace (
+	"` + sourceModule + `/tools" v0.1.0 => ` + sourceModule + `/tools/v2 v2.0.0
+)
+`
+	expected := `module github.com/acme/orders // main module
+
+go 1.24.0
+
+require (
+	github.com/stretchr/testify v1.11.1
+	github.com/acme/orders/sdk v0.1.0
+	` + sourceModule + `x v0.1.0
+)
+
+replace github.com/acme/orders/sdk => ./sdk
+
+replace (
+	"github.com/acme/orders/tools" v0.1.0 => github.com/acme/orders/tools/v2 v2.0.0
+)
+`
+
+	out, err := GoModuleTransform(sourceModule, "github.com/acme/orders")("go.mod", []byte(input))
+	require.NoError(t, err)
+	require.Equal(t, expected, string(out))
+}
+
+func TestGoModuleTransformImports(t *testing.T) {
+	t.Parallel()
+
+	input := `package main
+
+import (
+	"fmt"
+
+	cfg "` + sourceModule + `/internal/config"
+	"` + sourceModule + `/pkg/logger"
+	"github.com/other/` + "mcp" + `"
+)
+
+import _ "` + sourceModule + `"
+
+const doc = "` + sourceModule + `/internal/config"
+
+func main() { fmt.Println(cfg.X, logger.Y, doc) }
+`
+	expected := `package main
+
+import (
+	"fmt"
+
+	cfg "github.com/acme/orders/internal/config"
+	"github.com/acme/orders/pkg/logger"
+	"github.com/other/mcp"
+)
+
+import _ "github.com/acme/orders"
+
+const doc = "` + sourceModule + `/internal/config"
+
+func main() { fmt.Println(cfg.X, logger.Y, doc) }
+`
+
+	out, err := GoModuleTransform(sourceModule, "github.com/acme/orders")("cmd/main.go", []byte(input))
+	require.NoError(t, err)
+	require.Equal(t, expected, string(out))
+}
+
+func TestGoModuleTransformIgnoresOtherFiles(t *testing.T) {
+	t.Parallel()
+
+	transform := GoModuleTransform(sourceModule, "github.com/acme/orders")
+
+	readme := []byte("import " + sourceModule)
+	out, err := transform("README.md", readme)
+	require.NoError(t, err)
+	require.Equal(t, readme, out)
+
+	broken := []byte("package main\nimport ( \"" + sourceModule + "\"")
+	out, err = transform("broken.go", broken)
+	require.NoError(t, err)
+	require.Equal(t, broken, out)
+}
+
+func TestGoModulesTransformLongestPrefix(t *testing.T) {
+	t.Parallel()
+
+	transform := GoModulesTransform([]string{"github.com/acme", "github.com/vertikon/derived"}, "github.com/acme/orders")
+	input := `package main
+
+import (
+	"github.com/acme/lib"
+	"github.com/acme/orders/internal/app"
+	"github.com/vertikon/derived/internal/api"
+)
+`
+	expected := `package main
+
+import (
+	"github.com/acme/orders/lib"
+	"github.com/acme/orders/internal/app"
+	"github.com/acme/orders/internal/api"
+)
+`
+	out, err := transform("main.go", []byte(input))
+	require.NoError(t, err)
+	require.Equal(t, expected, string(out))
+
+	out, err = transform("go.mod", []byte("module github.com/vertikon/derived\n\nrequire github.com/acme v1.0.0\n"))
+	require.NoError(t, err)
+	require.Equal(t, "module github.com/acme/orders\n\nrequire github.com/acme/orders v1.0.0\n", string(out))
+}
@@ -0,0 +1,172 @@
+package template
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile é o arquivo, na raiz do template ou em subdiretórios, com padrões
+// no formato .gitignore de caminhos que não geram saída.
+const IgnoreFile = ".templateignore"
+
+// DefaultIgnore são padrões aplicados antes de qualquer .templateignore, cobrindo
+// arquivos de sistema operacional e editores. Podem ser revertidos com "!".
+var DefaultIgnore = []string{
+	".git/",
+	".DS_Store",
+	"Thumbs.db",
+	"desktop.ini",
+	".idea/",
+	"*.swp",
+	"*.swo",
+	"*~",
+}
+
+// ignorePattern é uma linha de .templateignore já interpretada.
+type ignorePattern struct {
+	// base é o diretório do arquivo de origem, relativo à raiz; vazio na raiz.
+	base     string
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// ignoreMatcher aplica padrões no formato .gitignore: o último padrão que casa
+// decide e "!" reinclui o caminho. Como no git, o conteúdo de um diretório
+// ignorado não pode ser reincluído.
+type ignoreMatcher struct {
+	layers   []string
+	patterns []ignorePattern
+	// forced são avaliados por último e não podem ser revertidos pelos arquivos.
+	forced []ignorePattern
+}
+
+func newIgnoreMatcher(layers, forced []string) (*ignoreMatcher, error) {
+	m := &ignoreMatcher{layers: layers}
+	defaults, err := parseIgnore("", "default", DefaultIgnore)
+	if err != nil {
+		return nil, err
+	}
+	m.patterns = defaults
+	if m.forced, err = parseIgnore("", "options", forced); err != nil {
+		return nil, err
+	}
+	if err := m.load("."); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// load lê o .templateignore de dir em cada camada, da base para o derivado.
+func (m *ignoreMatcher) load(dir string) error {
+	base := dir
+	if base == "." {
+		base = ""
+	}
+	for _, layer := range m.layers {
+		path := filepath.Join(layer, filepath.FromSlash(dir), IgnoreFile)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", IgnoreFile, err)
+		}
+
+		var lines []string
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		patterns, err := parseIgnore(base, pathpkg.Join(dir, IgnoreFile), lines)
+		if err != nil {
+			return err
+		}
+		m.patterns = append(m.patterns, patterns...)
+	}
+	return nil
+}
+
+// ignored informa se rel deve ser omitido. O próprio .templateignore nunca gera saída.
+func (m *ignoreMatcher) ignored(rel string, isDir bool) bool {
+	if !isDir && pathpkg.Base(rel) == IgnoreFile {
+		return true
+	}
+	result := false
+	for _, patterns := range [][]ignorePattern{m.patterns, m.forced} {
+		for _, p := range patterns {
+			if p.match(rel, isDir) {
+				result = !p.negate
+			}
+		}
+	}
+	return result
+}
+
+func (p ignorePattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if p.base != "" {
+		if !strings.HasPrefix(rel, p.base+"/") {
+			return false
+		}
+		rel = rel[len(p.base)+1:]
+	}
+	name := splitPath(rel)
+	// "dir/**" casa com o conteúdo do diretório, mas não com ele próprio.
+	if p.segments[len(p.segments)-1] == "**" && len(name) < len(p.segments) {
+		return false
+	}
+	return matchSegments(p.segments, name)
+}
+
+// parseIgnore interpreta linhas no formato .gitignore. source identifica a
+// origem nas mensagens de erro.
+func parseIgnore(base, source string, lines []string) ([]ignorePattern, error) {
+	var patterns []ignorePattern
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+			line = line[:len(line)-1]
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := ignorePattern{base: base}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// Padrões sem "/" casam em qualquer profundidade; os demais são relativos
+		// ao diretório do arquivo.
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+		if !ValidGlob(line) {
+			return nil, fmt.Errorf("invalid pattern in %s:%d: %s", source, i+1, lines[i])
+		}
+		p.segments = strings.Split(line, "/")
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,96 @@
+package template
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnorePatterns(t *testing.T) {
+	t.Parallel()
+
+	patterns, err := parseIgnore("", "test", []string{
+		"# comentário",
+		"",
+		"*.log",
+		"!keep.log",
+		"build/",
+		"/root-only.txt",
+		"docs/**/draft.md",
+		"tmp/**",
+		`\#literal`,
+	})
+	require.NoError(t, err)
+	m := &ignoreMatcher{patterns: patterns}
+
+	cases := []struct {
+		rel     string
+		isDir   bool
+		ignored bool
+	}{
+		{"app.log", false, true},
+		{"nested/app.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"src/build", true, true},
+		{"root-only.txt", false, true},
+		{"sub/root-only.txt", false, false},
+		{"docs/draft.md", false, true},
+		{"docs/a/b/draft.md", false, true},
+		{"tmp", true, false},
+		{"tmp/cache", false, true},
+		{"#literal", false, true},
+		{".templateignore", false, true},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.ignored, m.ignored(tc.rel, tc.isDir), tc.rel)
+	}
+
+	_, err = parseIgnore("", "test", []string{"a/[b"})
+	require.ErrorContains(t, err, "test:1")
+}
+
+func TestBuildTreeTemplateIgnore(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	writeFiles(t, base, map[string]string{
+		".templateignore": "*.bak\n",
+		"Makefile":        "build:\n",
+		"Makefile.bak":    "old\n",
+	})
+	src := t.TempDir()
+	writeFiles(t, src, map[string]string{
+		".templateignore":       "coverage/\n!.idea/\nnotes.md\n",
+		"desktop.ini":           "junk",
+		".idea/workspace.xml":   "kept",
+		"coverage/out.html":     "junk",
+		"main.go":               "package main\n",
+		"main.go.bak":           "junk",
+		"docs/.templateignore":  "*.md\n!README.md\n",
+		"docs/README.md":        "# docs\n",
+		"docs/intro.md":         "junk",
+		"docs/notes.md":         "junk",
+		"template.yaml":         "name: demo\n",
+		"other/template.yaml":   "kept",
+		"other/README.md":       "kept",
+		"other/notes/index.txt": "kept",
+	})
+
+	tree, err := BuildTree(context.Background(), src, nil, RenderOptions{
+		Bases:  []string{base},
+		Ignore: []string{"/template.yaml"},
+	})
+	require.NoError(t, err)
+
+	var files []string
+	for _, file := range tree.Files {
+		files = append(files, file.Path)
+	}
+	require.ElementsMatch(t, []string{
+		".idea/workspace.xml", "Makefile", "docs/README.md", "main.go",
+		"other/template.yaml", "other/README.md", "other/notes/index.txt",
+	}, files)
+}
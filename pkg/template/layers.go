@@ -0,0 +1,125 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// sourceEntry é um arquivo ou diretório da visão combinada das camadas do template.
+type sourceEntry struct {
+	// rel é o caminho relativo à raiz das camadas, separado por "/".
+	rel string
+	// path é o caminho absoluto na camada que fornece a entrada.
+	path  string
+	isDir bool
+}
+
+// overlay combina as camadas em uma única árvore. As camadas vêm da base para o
+// template derivado: um arquivo em uma camada posterior substitui o de mesmo
+// caminho nas anteriores, e diretórios são unidos. O resultado segue a ordem de
+// filepath.WalkDir (diretórios antes do conteúdo, nomes em ordem lexical).
+func overlay(layers []string) ([]sourceEntry, error) {
+	entries := make(map[string]sourceEntry)
+	for _, layer := range layers {
+		err := filepath.WalkDir(layer, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(layer, path)
+			if err != nil {
+				return err
+			}
+			if rel == "." {
+				return nil
+			}
+			slashRel := filepath.ToSlash(rel)
+			if previous, ok := entries[slashRel]; ok && previous.isDir != d.IsDir() {
+				return fmt.Errorf("layer conflict: %s is a file in one template and a directory in another", slashRel)
+			}
+			entries[slashRel] = sourceEntry{rel: slashRel, path: path, isDir: d.IsDir()}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]sourceEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return walkLess(result[i].rel, result[j].rel) })
+	return result, nil
+}
+
+// walkLess compara caminhos segmento a segmento, reproduzindo a ordem de WalkDir.
+func walkLess(a, b string) bool {
+	sa, sb := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(sa) && i < len(sb); i++ {
+		if sa[i] != sb[i] {
+			return sa[i] < sb[i]
+		}
+	}
+	return len(sa) < len(sb)
+}
+
+// loadPartials interpreta os arquivos dos diretórios de partials como templates
+// nomeados, disponíveis em qualquer arquivo via {{ template "nome" . }}. O nome é
+// o caminho relativo ao diretório sem extensões (license-header.go.tmpl vira
+// "license-header"). Diretórios posteriores podem redefinir partials anteriores.
+// Os delimitadores seguem o caminho do partial relativo à sua camada.
+func loadPartials(dirs, layers []string, mode *contentMode) (*template.Template, error) {
+	root := template.New("").Funcs(funcMap()).Funcs(mode.runtime.funcs("")).Option("missingkey=error")
+	for _, dir := range dirs {
+		prefix := layerRel(dir, layers)
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read partial: %w", err)
+			}
+			left, right := mode.delims(pathpkg.Join(prefix, filepath.ToSlash(rel)))
+			if _, err := root.New(partialName(filepath.ToSlash(rel))).Delims(left, right).Parse(string(data)); err != nil {
+				return fmt.Errorf("parse partial %s: %w", rel, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// layerRel retorna dir relativo à camada que o contém, separado por "/".
+func layerRel(dir string, layers []string) string {
+	for _, layer := range layers {
+		if rel, err := filepath.Rel(layer, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.Base(dir)
+}
+
+func partialName(rel string) string {
+	dir, base := pathpkg.Split(rel)
+	if idx := strings.Index(base, "."); idx > 0 {
+		base = base[:idx]
+	}
+	return dir + base
+}
@@ -0,0 +1,95 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestBuildTreeBasesAndPartials(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	writeFiles(t, base, map[string]string{
+		"Makefile":                      "build:\n",
+		"Dockerfile":                    "FROM base\n",
+		"pkg/log/log.go.tmpl":           "{{ template \"license-header\" . }}package log\n",
+		"_partials/license-header.tmpl": "// Copyright {{ .owner }}\n",
+	})
+	derived := t.TempDir()
+	writeFiles(t, derived, map[string]string{
+		"Dockerfile": "FROM derived\n",
+		"main.go":    "package main\n",
+	})
+
+	tree, err := BuildTree(context.Background(), derived, map[string]any{"owner": "ACME"}, RenderOptions{
+		Ignore:   []string{"/_partials/"},
+		Bases:    []string{base},
+		Partials: []string{filepath.Join(base, "_partials")},
+	})
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range tree.Files {
+		files[file.Path] = string(file.Content)
+	}
+	require.Equal(t, map[string]string{
+		"Dockerfile":     "FROM derived\n",
+		"Makefile":       "build:\n",
+		"main.go":        "package main\n",
+		"pkg/log/log.go": "// Copyright ACME\npackage log\n",
+	}, files)
+}
+
+func TestBuildTreeLayerConflict(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	writeFiles(t, base, map[string]string{"docs": "file\n"})
+	derived := t.TempDir()
+	writeFiles(t, derived, map[string]string{"docs/README.md": "dir\n"})
+
+	_, err := BuildTree(context.Background(), derived, map[string]any{}, RenderOptions{Bases: []string{base}})
+	require.ErrorContains(t, err, "layer conflict: docs")
+}
+
+func TestLoadPartialsNames(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"license-header.go.tmpl": "lic",
+		"ci/steps.yaml":          "steps",
+	})
+	override := t.TempDir()
+	writeFiles(t, override, map[string]string{"license-header.txt": "override"})
+
+	root, err := loadPartials([]string{dir, override}, nil, &contentMode{})
+	require.NoError(t, err)
+	require.NotNil(t, root.Lookup("ci/steps"))
+
+	var out strings.Builder
+	require.NoError(t, root.ExecuteTemplate(&out, "license-header", nil))
+	require.Equal(t, "override", out.String())
+}
+
+func TestWalkLess(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, walkLess("a", "a/b"))
+	require.True(t, walkLess("a/b", "a-b"))
+	require.False(t, walkLess("b", "a/z"))
+}
@@ -0,0 +1,57 @@
+package template
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// forEach executa fn para os índices de 0 a count-1 em até workers goroutines;
+// workers zero ou negativo usa GOMAXPROCS. O primeiro erro, ou o cancelamento
+// de ctx, interrompe a distribuição de novos índices. Entre os erros, vale o
+// de menor índice, para que a mensagem não dependa do escalonamento.
+func forEach(ctx context.Context, workers, count int, fn func(i int) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > count {
+		workers = count
+	}
+
+	stop, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, count)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(i); err != nil {
+					errs[i] = err
+					cancel()
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < count; i++ {
+		select {
+		case <-stop.Done():
+			break feed
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
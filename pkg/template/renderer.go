@@ -3,9 +3,13 @@ package template
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"os"
+	pathpkg "path"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -21,112 +25,466 @@ func (e ErrMissingVariable) Error() string {
 	return fmt.Sprintf("variável obrigatória ausente: %s", e.Key)
 }
 
+// ErrInvalidVariable indica que o valor informado não respeita a definição da variável.
+type ErrInvalidVariable struct {
+	Key    string
+	Reason string
+}
+
+func (e ErrInvalidVariable) Error() string {
+	return fmt.Sprintf("variável inválida %s: %s", e.Key, e.Reason)
+}
+
 // RenderOptions encapsula opções de geração.
 type RenderOptions struct {
-	IgnoredPaths map[string]struct{}
+	// Ignore são padrões no formato .gitignore, relativos à raiz, avaliados depois
+	// de DefaultIgnore e dos arquivos .templateignore, que não podem revertê-los.
+	Ignore []string
+	// Rules são avaliadas em ordem; a última regra aplicável decide se o caminho é gerado.
+	Rules []PathRule
+	// Transforms são aplicados, em ordem, ao conteúdo renderizado de arquivos de texto.
+	Transforms []Transform
+	// Bases são diretórios de templates herdados, do mais básico ao mais próximo;
+	// arquivos de src substituem os de mesmo caminho nas bases.
+	Bases []string
+	// Partials são diretórios cujos arquivos ficam disponíveis como templates nomeados.
+	Partials []string
+	// Delimiters são avaliadas em ordem; a última regra aplicável define os
+	// delimitadores do arquivo. Nomes de arquivos sempre usam {{ }}.
+	Delimiters []DelimiterRule
+	// CopyOnly lista padrões de arquivos copiados byte a byte, sem interpretação.
+	CopyOnly []string
+	// RenderOnly, quando informado, restringe a interpretação aos arquivos que
+	// casam com algum padrão; os demais são copiados.
+	RenderOnly []string
+	// Runtime habilita now e uuid no conteúdo dos arquivos e nos partials; nil
+	// as mantém indefinidas.
+	Runtime *Runtime
+	// Concurrency limita quantos arquivos são renderizados e gravados ao mesmo
+	// tempo; zero ou negativo usa GOMAXPROCS.
+	Concurrency int
+}
+
+// PathRule inclui ou exclui caminhos que casam com Pattern quando a condição When é verdadeira.
+// Uma regra sem When sempre se aplica. Caminhos herdam a decisão do diretório pai.
+type PathRule struct {
+	Pattern string
+	Exclude bool
+	When    string
+}
+
+type compiledRule struct {
+	PathRule
+	when *Condition
+}
+
+// FileKind indica como o conteúdo de um arquivo foi produzido.
+type FileKind string
+
+// Tipos de arquivo da árvore renderizada.
+const (
+	// FileRendered foi interpretado como template.
+	FileRendered FileKind = "template"
+	// FileCopied casou com copy_only ou ficou fora de render_only.
+	FileCopied FileKind = "copy"
+	// FileBinary foi detectado como binário e copiado.
+	FileBinary FileKind = "binary"
+)
+
+// File é um arquivo da árvore renderizada. Arquivos interpretados ficam em
+// memória; binários e copy_only não são carregados e são copiados de Origin em
+// streaming.
+type File struct {
+	// Path é o caminho de destino relativo, separado por "/".
+	Path string
+	// Source é o caminho relativo do arquivo de origem no template.
+	Source string
+	Mode   os.FileMode
+	Kind   FileKind
+	// Content é o conteúdo renderizado; nil em arquivos copiados.
+	Content []byte
+	// Origin é o caminho do arquivo copiado sem interpretação; vazio em
+	// arquivos renderizados.
+	Origin string
+	Size   int64
+	// Hash é o SHA-256 do conteúdo, em hexadecimal. Em arquivos copiados, é
+	// calculado durante Write ou por Digest.
+	Hash string
+}
+
+// Bytes retorna o conteúdo do arquivo, lendo Origin quando ele é copiado.
+func (f *File) Bytes() ([]byte, error) {
+	if f.Origin == "" {
+		return f.Content, nil
+	}
+	data, err := os.ReadFile(f.Origin)
+	if err != nil {
+		return nil, fmt.Errorf("read source file: %w", err)
+	}
+	return data, nil
+}
+
+// Digest retorna Hash, calculando-o em streaming a partir de Origin se o
+// arquivo ainda não foi gravado.
+func (f *File) Digest() (string, error) {
+	if f.Hash != "" {
+		return f.Hash, nil
+	}
+	in, err := os.Open(f.Origin)
+	if err != nil {
+		return "", fmt.Errorf("open source file: %w", err)
+	}
+	defer in.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, in); err != nil {
+		return "", fmt.Errorf("hash source file: %w", err)
+	}
+	f.Hash = hex.EncodeToString(h.Sum(nil))
+	return f.Hash, nil
+}
+
+// Tree é o resultado da renderização de um diretório.
+type Tree struct {
+	Dirs  []string
+	Files []File
+	// concurrency é herdada de RenderOptions e usada por Write.
+	concurrency int
+}
+
+// pendingFile é um arquivo selecionado pelo percurso, ainda não renderizado.
+type pendingFile struct {
+	path       string
+	source     string
+	target     string
+	isTemplate bool
 }
 
 // RenderDirectory processa os arquivos em src e grava em dst aplicando as variáveis.
-func RenderDirectory(ctx context.Context, src, dst string, values map[string]string, opts RenderOptions) error {
-	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
+func RenderDirectory(ctx context.Context, src, dst string, values map[string]any, opts RenderOptions) error {
+	tree, err := BuildTree(ctx, src, values, opts)
+	if err != nil {
+		return err
+	}
+	return tree.Write(ctx, dst)
+}
+
+// BuildTree avalia regras, nomes e conteúdos dos arquivos em src sem tocar o disco de destino.
+func BuildTree(ctx context.Context, src string, values map[string]any, opts RenderOptions) (*Tree, error) {
+	rules, err := compileRules(opts.Rules)
+	if err != nil {
+		return nil, err
+	}
+	hasIncludes := false
+	for _, rule := range rules {
+		hasIncludes = hasIncludes || !rule.Exclude
+	}
+	included := map[string]bool{".": true}
+	targets := map[string]string{".": ""}
+	skipped := make(map[string]bool)
+	sources := make(map[string]string)
+	tree := &Tree{concurrency: opts.Concurrency}
+	var pending []pendingFile
+
+	mode, err := newContentMode(opts)
+	if err != nil {
+		return nil, err
+	}
+	layers := append(append([]string(nil), opts.Bases...), src)
+	entries, err := overlay(layers)
+	if err != nil {
+		return nil, err
+	}
+	partials, err := loadPartials(opts.Partials, layers, mode)
+	if err != nil {
+		return nil, err
+	}
+	ignore, err := newIgnoreMatcher(layers, opts.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		slashRel := entry.rel
+		parent := pathpkg.Dir(slashRel)
+		if skipped[parent] {
+			if entry.isDir {
+				skipped[slashRel] = true
+			}
+			continue
 		}
-		rel, err := filepath.Rel(src, path)
-		if err != nil {
-			return err
+
+		if ignore.ignored(slashRel, entry.isDir) {
+			if entry.isDir {
+				skipped[slashRel] = true
+			}
+			continue
+		}
+		if entry.isDir {
+			if err := ignore.load(slashRel); err != nil {
+				return nil, err
+			}
 		}
 
-		if rel == "." {
-			return nil
+		keep := applyRules(rules, slashRel, included[parent], values)
+		if entry.isDir {
+			included[slashRel] = keep
+			if !keep && !hasIncludes {
+				skipped[slashRel] = true
+				continue
+			}
+		}
+		if !keep && !entry.isDir {
+			continue
 		}
 
-		if _, ignore := opts.IgnoredPaths[filepath.ToSlash(rel)]; ignore {
-			if d.IsDir() {
-				return filepath.SkipDir
+		name, err := renderPathSegment(pathpkg.Base(slashRel), values)
+		if err != nil {
+			return nil, fmt.Errorf("render path %s: %w", slashRel, err)
+		}
+		if name == "" {
+			if entry.isDir {
+				skipped[slashRel] = true
 			}
-			return nil
+			continue
 		}
 
-		targetPath := filepath.Join(dst, rel)
+		targetRel := pathpkg.Join(targets[parent], name)
+		isTemplate := false
+		if entry.isDir {
+			targets[slashRel] = targetRel
+			if !keep {
+				continue
+			}
+		} else if strings.HasSuffix(targetRel, ".tmpl") {
+			targetRel = strings.TrimSuffix(targetRel, ".tmpl")
+			isTemplate = true
+		}
 
-		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0o755)
+		if previous, exists := sources[targetRel]; exists {
+			return nil, fmt.Errorf("path collision: %s and %s both render to %s", previous, slashRel, targetRel)
 		}
+		sources[targetRel] = slashRel
 
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
+		if entry.isDir {
+			tree.Dirs = append(tree.Dirs, targetRel)
+			continue
 		}
+		pending = append(pending, pendingFile{path: entry.path, source: slashRel, target: targetRel, isTemplate: isTemplate})
+	}
 
-		return renderFile(path, targetPath, values)
+	// Os conteúdos são avaliados em paralelo; a ordem de Files segue o percurso.
+	tree.Files = make([]File, len(pending))
+	err = forEach(ctx, opts.Concurrency, len(pending), func(i int) error {
+		p := pending[i]
+		file, err := renderFile(p.path, p.source, p.target, p.isTemplate, values, opts.Transforms, mode, partials)
+		if err != nil {
+			return err
+		}
+		tree.Files[i] = file
+		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	return tree, nil
 }
 
-func renderFile(src, dst string, values map[string]string) error {
-	info, err := os.Stat(src)
-	if err != nil {
-		return fmt.Errorf("stat source file: %w", err)
+// Write grava diretórios e arquivos da árvore em dst, com a mesma concorrência
+// usada na renderização. Arquivos copiados são transferidos em streaming e têm
+// o Hash calculado durante a cópia. Cancelar ctx interrompe a gravação dos
+// arquivos restantes.
+func (t *Tree) Write(ctx context.Context, dst string) error {
+	for _, dir := range t.Dirs {
+		if err := os.MkdirAll(filepath.Join(dst, filepath.FromSlash(dir)), 0o755); err != nil {
+			return fmt.Errorf("ensure target dir: %w", err)
+		}
 	}
+	return forEach(ctx, t.concurrency, len(t.Files), func(i int) error {
+		file := &t.Files[i]
+		target := filepath.Join(dst, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("ensure target dir: %w", err)
+		}
+		if file.Origin == "" {
+			if err := os.WriteFile(target, file.Content, file.Mode); err != nil {
+				return fmt.Errorf("write rendered file: %w", err)
+			}
+			return nil
+		}
+		h := sha256.New()
+		if err := copyFile(target, file.Origin, file.Mode, h); err != nil {
+			return err
+		}
+		file.Hash = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+}
 
-	data, err := os.ReadFile(src)
+// copyFile copia src para dst em streaming, alimentando h com o conteúdo.
+func copyFile(dst, src string, mode os.FileMode, h hash.Hash) error {
+	in, err := os.Open(src)
 	if err != nil {
-		return fmt.Errorf("read source file: %w", err)
+		return fmt.Errorf("open source file: %w", err)
 	}
+	defer in.Close()
 
-	isTemplate := strings.HasSuffix(dst, ".tmpl")
-	if isTemplate {
-		dst = strings.TrimSuffix(dst, ".tmpl")
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("write copied file: %w", err)
 	}
-
-	if !isTemplate && looksBinary(data) {
-		return copyBinary(dst, data, info.Mode())
+	if _, err := io.Copy(io.MultiWriter(out, h), in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("write copied file: %w", err)
 	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("write copied file: %w", err)
+	}
+	return nil
+}
 
-	tmpl, err := template.New(filepath.Base(src)).
+// RenderString avalia um texto curto (nomes de arquivos, variáveis de ambiente)
+// com as mesmas funções e valores usados no conteúdo dos arquivos.
+func RenderString(name, text string, values map[string]any) (string, error) {
+	tmpl, err := template.New(name).
 		Funcs(funcMap()).
 		Option("missingkey=error").
-		Parse(string(data))
+		Parse(text)
 	if err != nil {
-		return fmt.Errorf("parse template: %w", err)
+		return "", fmt.Errorf("parse template: %w", err)
 	}
 
-	var buf bytes.Buffer
+	var buf strings.Builder
 	if err := tmpl.Execute(&buf, values); err != nil {
-		return fmt.Errorf("execute template: %w", err)
+		return "", fmt.Errorf("execute template: %w", err)
 	}
+	return buf.String(), nil
+}
 
-	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
-		return fmt.Errorf("ensure target dir: %w", err)
+// renderPathSegment expande um segmento de caminho. Um resultado vazio indica
+// que a entrada deve ser omitida; separadores de diretório não são permitidos.
+func renderPathSegment(segment string, values map[string]any) (string, error) {
+	if !strings.Contains(segment, "{{") {
+		return segment, nil
 	}
+	rendered, err := RenderString(segment, segment, values)
+	if err != nil {
+		return "", err
+	}
+	rendered = strings.TrimSpace(rendered)
+	if strings.ContainsAny(rendered, `/\`) || rendered == "." || rendered == ".." {
+		return "", fmt.Errorf("segment %q rendered to invalid name %q", segment, rendered)
+	}
+	return rendered, nil
+}
 
-	if err := os.WriteFile(dst, buf.Bytes(), info.Mode()); err != nil {
-		return fmt.Errorf("write rendered file: %w", err)
+func compileRules(rules []PathRule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		if !ValidGlob(rule.Pattern) {
+			return nil, fmt.Errorf("invalid rule pattern: %s", rule.Pattern)
+		}
+		c := compiledRule{PathRule: rule}
+		if strings.TrimSpace(rule.When) != "" {
+			cond, err := ParseCondition(rule.When)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rule for %s: %w", rule.Pattern, err)
+			}
+			c.when = cond
+		}
+		compiled = append(compiled, c)
 	}
+	return compiled, nil
+}
 
-	return nil
+func applyRules(rules []compiledRule, rel string, inherited bool, values map[string]any) bool {
+	keep := inherited
+	for _, rule := range rules {
+		if !MatchGlob(rule.Pattern, rel) {
+			continue
+		}
+		if rule.when != nil && !rule.when.Eval(values) {
+			continue
+		}
+		keep = !rule.Exclude
+	}
+	return keep
 }
 
-func copyBinary(dst string, data []byte, mode os.FileMode) error {
-	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
-		return fmt.Errorf("ensure target dir: %w", err)
+func renderFile(src, source, rel string, isTemplate bool, values map[string]any, transforms []Transform, mode *contentMode, partials *template.Template) (File, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return File{}, fmt.Errorf("stat source file: %w", err)
 	}
 
-	if err := os.WriteFile(dst, data, mode); err != nil {
-		return fmt.Errorf("write binary file: %w", err)
+	file := File{Path: rel, Source: source, Mode: info.Mode(), Kind: FileRendered}
+	copied := File{Path: rel, Source: source, Mode: info.Mode(), Kind: FileCopied, Origin: src, Size: info.Size()}
+	if !mode.render(source, isTemplate) {
+		return copied, nil
 	}
-	return nil
+
+	// Só o início do arquivo é lido para detectar binários, que são copiados
+	// sem carregar o restante.
+	in, err := os.Open(src)
+	if err != nil {
+		return File{}, fmt.Errorf("read source file: %w", err)
+	}
+	defer in.Close()
+	head := make([]byte, binarySniffLength)
+	n, err := io.ReadFull(in, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return File{}, fmt.Errorf("read source file: %w", err)
+	}
+	head = head[:n]
+	if !isTemplate && looksBinary(head) {
+		copied.Kind = FileBinary
+		return copied, nil
+	}
+	rest, err := io.ReadAll(in)
+	if err != nil {
+		return File{}, fmt.Errorf("read source file: %w", err)
+	}
+	data := append(head, rest...)
+
+	root, err := partials.Clone()
+	if err != nil {
+		return File{}, fmt.Errorf("clone partials: %w", err)
+	}
+	root.Funcs(mode.runtime.funcs(source))
+	tmpl, err := root.New(filepath.Base(src)).Delims(mode.delims(source)).Parse(string(data))
+	if err != nil {
+		return File{}, fmt.Errorf("parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return File{}, fmt.Errorf("execute template: %w", err)
+	}
+
+	content := buf.Bytes()
+	for _, transform := range transforms {
+		if content, err = transform(rel, content); err != nil {
+			return File{}, fmt.Errorf("transform %s: %w", rel, err)
+		}
+	}
+	sum := sha256.Sum256(content)
+	file.Content = content
+	file.Size = int64(len(content))
+	file.Hash = hex.EncodeToString(sum[:])
+	return file, nil
 }
 
+// binarySniffLength é quanto do início de um arquivo é examinado por looksBinary.
+const binarySniffLength = 8000
+
 func looksBinary(data []byte) bool {
 	if len(data) == 0 {
 		return false
 	}
 
 	sample := data
-	if len(sample) > 8000 {
-		sample = sample[:8000]
+	if len(sample) > binarySniffLength {
+		sample = sample[:binarySniffLength]
 	}
 
 	for _, b := range sample {
@@ -138,7 +496,7 @@ func looksBinary(data []byte) bool {
 }
 
 func funcMap() template.FuncMap {
-	return template.FuncMap{
+	funcs := template.FuncMap{
 		"toUpper": strings.ToUpper,
 		"toLower": strings.ToLower,
 		"camel":   toCamel,
@@ -146,6 +504,10 @@ func funcMap() template.FuncMap {
 		"snake":   toSnake,
 		"title":   strings.Title, //nolint:staticcheck
 	}
+	for name, fn := range libraryFuncs() {
+		funcs[name] = fn
+	}
+	return funcs
 }
 
 func toCamel(input string) string {
@@ -212,4 +574,3 @@ func CopyFile(dst string, r io.Reader, mode os.FileMode) error {
 	}
 	return nil
 }
-
@@ -3,9 +3,14 @@ package template
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -25,11 +30,9 @@ func TestRenderDirectory(t *testing.T) {
 	binaryPath := filepath.Join(tmpSrc, "module.wasm")
 	require.NoError(t, os.WriteFile(binaryPath, []byte{0x00, 0x61, 0x62, 0x63}, 0o644))
 
-	err = RenderDirectory(context.Background(), tmpSrc, tmpDst, map[string]string{
+	err = RenderDirectory(context.Background(), tmpSrc, tmpDst, map[string]any{
 		"service_name": "demo",
-	}, RenderOptions{
-		IgnoredPaths: map[string]struct{}{},
-	})
+	}, RenderOptions{})
 	require.NoError(t, err)
 
 	renderedText := filepath.Join(tmpDst, "nested", "config.yaml")
@@ -49,9 +52,7 @@ func TestRenderDirectoryMissingVariable(t *testing.T) {
 	tmpSrc := t.TempDir()
 	require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, "config.yaml.tmpl"), []byte("{{ .missing }}"), 0o644))
 
-	err := RenderDirectory(context.Background(), tmpSrc, t.TempDir(), map[string]string{}, RenderOptions{
-		IgnoredPaths: map[string]struct{}{},
-	})
+	err := RenderDirectory(context.Background(), tmpSrc, t.TempDir(), map[string]any{}, RenderOptions{})
 	require.Error(t, err)
 }
 
@@ -107,10 +108,8 @@ func TestRenderDirectoryIgnorePaths(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, "keep", "value.txt.tmpl"), []byte("value: {{ .val }}"), 0o644))
 
 	tmpDst := t.TempDir()
-	err := RenderDirectory(context.Background(), tmpSrc, tmpDst, map[string]string{"val": "ok"}, RenderOptions{
-		IgnoredPaths: map[string]struct{}{
-			"skip": {},
-		},
+	err := RenderDirectory(context.Background(), tmpSrc, tmpDst, map[string]any{"val": "ok"}, RenderOptions{
+		Ignore: []string{"skip"},
 	})
 	require.NoError(t, err)
 
@@ -131,8 +130,263 @@ func TestRenderDirectoryContextCanceled(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
-	err := RenderDirectory(ctx, tmpSrc, t.TempDir(), map[string]string{}, RenderOptions{
-		IgnoredPaths: map[string]struct{}{},
+	err := RenderDirectory(ctx, tmpSrc, t.TempDir(), map[string]any{}, RenderOptions{})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRenderDirectoryRules(t *testing.T) {
+	t.Parallel()
+
+	tmpSrc := t.TempDir()
+	for _, file := range []string{
+		"internal/grpc/server.go",
+		"internal/grpc/keep/README.md",
+		"internal/nats/client.go",
+		"main.go",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpSrc, filepath.Dir(file)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, file), []byte(file), 0o644))
+	}
+
+	tmpDst := t.TempDir()
+	err := RenderDirectory(context.Background(), tmpSrc, tmpDst, map[string]any{
+		"enable_grpc": "false",
+		"enable_nats": "true",
+	}, RenderOptions{
+		Rules: []PathRule{
+			{Pattern: "internal/grpc", Exclude: true, When: "enable_grpc == false"},
+			{Pattern: "internal/grpc/keep/**"},
+			{Pattern: "internal/nats/**", Exclude: true, When: "!enable_nats"},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(tmpDst, "internal", "grpc", "server.go"))
+	require.True(t, os.IsNotExist(err))
+	require.FileExists(t, filepath.Join(tmpDst, "internal", "grpc", "keep", "README.md"))
+	require.FileExists(t, filepath.Join(tmpDst, "internal", "nats", "client.go"))
+	require.FileExists(t, filepath.Join(tmpDst, "main.go"))
+}
+
+func TestRenderDirectoryExcludedDirNotCreated(t *testing.T) {
+	t.Parallel()
+
+	tmpSrc := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpSrc, "dashboard", "static"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, "dashboard", "static", "app.js"), []byte("js"), 0o644))
+
+	tmpDst := t.TempDir()
+	err := RenderDirectory(context.Background(), tmpSrc, tmpDst, map[string]any{}, RenderOptions{
+		Rules: []PathRule{{Pattern: "dashboard/**", Exclude: true, When: "!enable_dashboard"}},
+	})
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(tmpDst, "dashboard"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRenderDirectoryInvalidRule(t *testing.T) {
+	t.Parallel()
+
+	err := RenderDirectory(context.Background(), t.TempDir(), t.TempDir(), map[string]any{}, RenderOptions{
+		Rules: []PathRule{{Pattern: "a/**", Exclude: true, When: "a =="}},
+	})
+	require.Error(t, err)
+}
+
+func TestRenderDirectoryPathTemplates(t *testing.T) {
+	t.Parallel()
+
+	tmpSrc := t.TempDir()
+	dir := filepath.Join(tmpSrc, "cmd", "{{ kebab .service_name }}")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go.tmpl"), []byte("package main // {{ .service_name }}"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, "{{ snake .service_name }}.yaml"), []byte("name: x"), 0o644))
+
+	optional := filepath.Join(tmpSrc, "{{ if .with_docs }}docs{{ end }}")
+	require.NoError(t, os.MkdirAll(optional, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(optional, "README.md"), []byte("docs"), 0o644))
+
+	tmpDst := t.TempDir()
+	err := RenderDirectory(context.Background(), tmpSrc, tmpDst, map[string]any{
+		"service_name": "Order Service",
+		"with_docs":    "",
+	}, RenderOptions{})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(tmpDst, "cmd", "order-service", "main.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package main // Order Service", string(data))
+	require.FileExists(t, filepath.Join(tmpDst, "order_service.yaml"))
+
+	entries, err := os.ReadDir(tmpDst)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func TestRenderDirectoryPathCollision(t *testing.T) {
+	t.Parallel()
+
+	tmpSrc := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, "{{ .a }}.txt"), []byte("a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, "{{ .b }}.txt"), []byte("b"), 0o644))
+
+	err := RenderDirectory(context.Background(), tmpSrc, t.TempDir(), map[string]any{
+		"a": "same",
+		"b": "same",
+	}, RenderOptions{})
+	require.ErrorContains(t, err, "path collision")
+}
+
+func TestRenderDirectoryTmplSuffixCollision(t *testing.T) {
+	t.Parallel()
+
+	tmpSrc := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, "config.yaml"), []byte("a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, "config.yaml.tmpl"), []byte("b"), 0o644))
+
+	err := RenderDirectory(context.Background(), tmpSrc, t.TempDir(), map[string]any{}, RenderOptions{})
+	require.ErrorContains(t, err, "path collision")
+}
+
+func TestRenderDirectoryPathSeparatorRejected(t *testing.T) {
+	t.Parallel()
+
+	tmpSrc := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpSrc, "{{ .name }}.txt"), []byte("a"), 0o644))
+
+	err := RenderDirectory(context.Background(), tmpSrc, t.TempDir(), map[string]any{
+		"name": "../escape",
+	}, RenderOptions{})
+	require.Error(t, err)
+}
+
+func TestBuildTreeStreamsCopiedFiles(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writeFiles(t, src, map[string]string{
+		"README.md":          "# {{ .name }}\n",
+		"static/logo.svg":    "<svg>{{ raw }}</svg>\n",
+		"static/wasm/m.wasm": "\x00asm\x01\x00\x00\x00",
+	})
+
+	tree, err := BuildTree(context.Background(), src, map[string]any{"name": "demo"}, RenderOptions{
+		CopyOnly: []string{"static/*.svg"},
+	})
+	require.NoError(t, err)
+
+	files := map[string]File{}
+	for _, file := range tree.Files {
+		files[file.Path] = file
+	}
+	readme := files["README.md"]
+	require.Equal(t, "# demo\n", string(readme.Content))
+	require.Empty(t, readme.Origin)
+	require.Equal(t, FileRendered, readme.Kind)
+	require.Equal(t, FileCopied, files["static/logo.svg"].Kind)
+	require.Equal(t, FileBinary, files["static/wasm/m.wasm"].Kind)
+	require.Equal(t, int64(len("# demo\n")), readme.Size)
+	sum := sha256.Sum256([]byte("# demo\n"))
+	require.Equal(t, hex.EncodeToString(sum[:]), readme.Hash)
+
+	for _, path := range []string{"static/logo.svg", "static/wasm/m.wasm"} {
+		require.Nil(t, files[path].Content, path)
+		require.Equal(t, filepath.Join(src, filepath.FromSlash(path)), files[path].Origin)
+		require.Empty(t, files[path].Hash, path)
+	}
+
+	dst := t.TempDir()
+	require.NoError(t, tree.Write(context.Background(), dst))
+	for i, file := range tree.Files {
+		data, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(file.Path)))
+		require.NoError(t, err)
+		sum := sha256.Sum256(data)
+		require.Equal(t, hex.EncodeToString(sum[:]), tree.Files[i].Hash, file.Path)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "static", "logo.svg"))
+	require.NoError(t, err)
+	require.Equal(t, "<svg>{{ raw }}</svg>\n", string(data))
+}
+
+func TestFileDigest(t *testing.T) {
+	t.Parallel()
+
+	src := filepath.Join(t.TempDir(), "blob.bin")
+	require.NoError(t, os.WriteFile(src, []byte("\x00blob"), 0o644))
+
+	file := File{Path: "blob.bin", Origin: src}
+	digest, err := file.Digest()
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte("\x00blob"))
+	require.Equal(t, hex.EncodeToString(sum[:]), digest)
+	require.Equal(t, digest, file.Hash)
+
+	data, err := file.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, []byte("\x00blob"), data)
+}
+
+func TestBuildTreeConcurrencyKeepsOrder(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	files := map[string]string{}
+	for i := 0; i < 50; i++ {
+		files[fmt.Sprintf("pkg%02d/file.txt", i)] = fmt.Sprintf("{{ .name }} %d\n", i)
+	}
+	writeFiles(t, src, files)
+
+	paths := func(concurrency int) []string {
+		tree, err := BuildTree(context.Background(), src, map[string]any{"name": "demo"}, RenderOptions{Concurrency: concurrency})
+		require.NoError(t, err)
+		var result []string
+		for _, file := range tree.Files {
+			require.Contains(t, string(file.Content), "demo ")
+			result = append(result, file.Path)
+		}
+		return result
+	}
+	sequential := paths(1)
+	require.Len(t, sequential, 50)
+	require.Equal(t, sequential, paths(8))
+}
+
+func TestForEach(t *testing.T) {
+	t.Parallel()
+
+	var running, peak int32
+	err := forEach(context.Background(), 3, 20, func(int) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			previous := atomic.LoadInt32(&peak)
+			if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	require.NoError(t, err)
+	require.LessOrEqual(t, peak, int32(3))
+
+	err = forEach(context.Background(), 4, 20, func(i int) error {
+		if i == 5 || i == 7 {
+			return fmt.Errorf("item %d", i)
+		}
+		return nil
+	})
+	require.EqualError(t, err, "item 5")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	err = forEach(ctx, 2, 1000, func(int) error {
+		if atomic.AddInt32(&calls, 1) == 3 {
+			cancel()
+		}
+		return nil
 	})
 	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, atomic.LoadInt32(&calls), int32(1000))
 }
@@ -0,0 +1,46 @@
+//go:build unix
+
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeWriteContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	// A cópia de um FIFO bloqueia até que alguém escreva nele, o que mantém
+	// Write ocupado com o primeiro arquivo enquanto o contexto é cancelado.
+	fifo := filepath.Join(t.TempDir(), "pipe")
+	require.NoError(t, syscall.Mkfifo(fifo, 0o600))
+
+	tree := &Tree{
+		Files: []File{
+			{Path: "pipe.txt", Origin: fifo, Mode: 0o644},
+			{Path: "a.txt", Content: []byte("a"), Mode: 0o644},
+			{Path: "b.txt", Content: []byte("b"), Mode: 0o644},
+		},
+		concurrency: 1,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dst := t.TempDir()
+	done := make(chan error, 1)
+	go func() { done <- tree.Write(ctx, dst) }()
+
+	writer, err := os.OpenFile(fifo, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	cancel()
+	require.NoError(t, writer.Close())
+
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.FileExists(t, filepath.Join(dst, "pipe.txt"))
+	require.NoFileExists(t, filepath.Join(dst, "a.txt"))
+	require.NoFileExists(t, filepath.Join(dst, "b.txt"))
+}
@@ -0,0 +1,278 @@
+// Package values manipula a árvore de valores usada na renderização: mapas
+// aninhados, listas e escalares vindos de YAML, JSON e flags.
+package values
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Separator separa os segmentos de um caminho como "database.host".
+const Separator = "."
+
+// Merge aplica src sobre dst recursivamente e retorna dst. Mapas são mesclados
+// chave a chave; listas e escalares de src substituem os de dst.
+func Merge(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			dst[key] = Merge(dstMap, srcMap)
+			continue
+		}
+		dst[key] = Clone(value)
+	}
+	return dst
+}
+
+// Clone copia mapas e listas em profundidade; escalares são compartilhados.
+func Clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = Clone(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = Clone(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// Lookup obtém o valor em path, percorrendo mapas aninhados.
+func Lookup(tree map[string]any, path string) (any, bool) {
+	if value, ok := tree[path]; ok {
+		return value, true
+	}
+	var current any = tree
+	for _, segment := range strings.Split(path, Separator) {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[segment]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Set grava value em path, criando mapas intermediários. Um segmento
+// intermediário que não é mapa é substituído.
+func Set(tree map[string]any, path string, value any) error {
+	segments := strings.Split(path, Separator)
+	for _, segment := range segments {
+		if strings.TrimSpace(segment) == "" {
+			return fmt.Errorf("invalid key %q", path)
+		}
+	}
+
+	current := tree
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := current[segment].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			current[segment] = next
+		}
+		current = next
+	}
+	current[segments[len(segments)-1]] = value
+	return nil
+}
+
+// Delete remove o valor em path e informa se ele existia. Mapas
+// intermediários são mantidos, mesmo que fiquem vazios.
+func Delete(tree map[string]any, path string) bool {
+	if _, ok := tree[path]; ok {
+		delete(tree, path)
+		return true
+	}
+	segments := strings.Split(path, Separator)
+	current := tree
+	for _, segment := range segments[:len(segments)-1] {
+		next, ok := current[segment].(map[string]any)
+		if !ok {
+			return false
+		}
+		current = next
+	}
+	last := segments[len(segments)-1]
+	if _, ok := current[last]; !ok {
+		return false
+	}
+	delete(current, last)
+	return true
+}
+
+// String converte um escalar para texto; nil vira vazio. Mapas e listas são
+// serializados como JSON.
+func String(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// IsScalar informa se value não é mapa nem lista.
+func IsScalar(value any) bool {
+	switch value.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return true
+}
+
+// Normalize converte a saída de decodificadores para os tipos da árvore:
+// map[any]any vira map[string]any e []map[string]any vira []any.
+func Normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = Normalize(item)
+		}
+		return v
+	case map[any]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = Normalize(item)
+		}
+		return result
+	case []any:
+		for i, item := range v {
+			v[i] = Normalize(item)
+		}
+		return v
+	case []map[string]any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = Normalize(item)
+		}
+		return result
+	case map[string]string:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[key] = item
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// Walk visita os escalares da árvore em ordem de caminho. Listas usam o índice
+// como segmento.
+func Walk(tree map[string]any, fn func(path string, value any)) {
+	walk("", tree, fn)
+}
+
+func walk(prefix string, value any, fn func(path string, value any)) {
+	join := func(segment string) string {
+		if prefix == "" {
+			return segment
+		}
+		return prefix + Separator + segment
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walk(join(key), v[key], fn)
+		}
+	case []any:
+		for i, item := range v {
+			walk(join(strconv.Itoa(i)), item, fn)
+		}
+	default:
+		fn(prefix, v)
+	}
+}
+
+// Layer é uma fonte de valores identificada, como os defaults do template, um
+// arquivo ou uma flag.
+type Layer struct {
+	Source string
+	Values map[string]any
+}
+
+// Resolve mescla as camadas em ordem, como Merge, e retorna a árvore final e,
+// para cada folha, a fonte que definiu seu valor. Listas são folhas, pois são
+// substituídas por inteiro.
+func Resolve(layers []Layer) (map[string]any, map[string]string) {
+	tree := make(map[string]any)
+	origins := make(map[string]string)
+	for _, layer := range layers {
+		resolve(tree, layer.Values, "", layer.Source, origins)
+	}
+	return tree, origins
+}
+
+func resolve(dst, src map[string]any, prefix, source string, origins map[string]string) {
+	for key, value := range src {
+		path := key
+		if prefix != "" {
+			path = prefix + Separator + key
+		}
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			resolve(dstMap, srcMap, path, source, origins)
+			continue
+		}
+
+		delete(origins, path)
+		for existing := range origins {
+			if strings.HasPrefix(existing, path+Separator) {
+				delete(origins, existing)
+			}
+		}
+		if srcIsMap {
+			dstMap = make(map[string]any, len(srcMap))
+			dst[key] = dstMap
+			if len(srcMap) == 0 {
+				origins[path] = source
+			}
+			resolve(dstMap, srcMap, path, source, origins)
+			continue
+		}
+		dst[key] = Clone(value)
+		origins[path] = source
+	}
+}
@@ -0,0 +1,120 @@
+package values
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeDeep(t *testing.T) {
+	t.Parallel()
+
+	defaults := map[string]any{
+		"database": map[string]any{"host": "localhost", "port": 5432},
+		"subjects": []any{"a"},
+	}
+	override := map[string]any{
+		"database": map[string]any{"host": "db"},
+		"subjects": []any{"b", "c"},
+	}
+
+	merged := Merge(Merge(nil, defaults), override)
+	require.Equal(t, map[string]any{
+		"database": map[string]any{"host": "db", "port": 5432},
+		"subjects": []any{"b", "c"},
+	}, merged)
+	require.Equal(t, "localhost", defaults["database"].(map[string]any)["host"])
+
+	merged["subjects"].([]any)[0] = "changed"
+	require.Equal(t, "b", override["subjects"].([]any)[0])
+}
+
+func TestSetAndLookup(t *testing.T) {
+	t.Parallel()
+
+	tree := map[string]any{"database": "flat"}
+	require.NoError(t, Set(tree, "database.host", "db"))
+	require.NoError(t, Set(tree, "database.port", 5432))
+
+	value, ok := Lookup(tree, "database.host")
+	require.True(t, ok)
+	require.Equal(t, "db", value)
+
+	_, ok = Lookup(tree, "database.user")
+	require.False(t, ok)
+
+	require.Error(t, Set(tree, "a..b", "x"))
+	require.Error(t, Set(tree, "", "x"))
+
+	require.True(t, Delete(tree, "database.host"))
+	require.False(t, Delete(tree, "database.host"))
+	require.False(t, Delete(tree, "database.port.x"))
+	require.Equal(t, map[string]any{"database": map[string]any{"port": 5432}}, tree)
+}
+
+func TestLookupPrefersLiteralKey(t *testing.T) {
+	t.Parallel()
+
+	tree := map[string]any{"app.name": "literal", "app": map[string]any{"name": "nested"}}
+	value, ok := Lookup(tree, "app.name")
+	require.True(t, ok)
+	require.Equal(t, "literal", value)
+}
+
+func TestString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "", String(nil))
+	require.Equal(t, "true", String(true))
+	require.Equal(t, "3", String(3))
+	require.Equal(t, "1.5", String(1.5))
+	require.Equal(t, `["a","b"]`, String([]any{"a", "b"}))
+	require.Equal(t, `{"k":"v"}`, String(map[string]any{"k": "v"}))
+}
+
+func TestNormalizeAndWalk(t *testing.T) {
+	t.Parallel()
+
+	tree := Normalize(map[string]any{
+		"services": []map[string]any{{"name": "users"}},
+		"labels":   map[any]any{"team": "core"},
+	}).(map[string]any)
+
+	var paths []string
+	Walk(tree, func(path string, value any) {
+		paths = append(paths, path+"="+String(value))
+	})
+	require.Equal(t, []string{"labels.team=core", "services.0.name=users"}, paths)
+}
+
+func TestResolveTracksOrigins(t *testing.T) {
+	t.Parallel()
+
+	tree, origins := Resolve([]Layer{
+		{Source: "defaults", Values: map[string]any{
+			"database": map[string]any{"host": "localhost", "port": 5432},
+			"cache":    map[string]any{"ttl": 60},
+			"env":      "dev",
+		}},
+		{Source: "prod.yaml", Values: map[string]any{
+			"database": map[string]any{"host": "db"},
+			"cache":    "disabled",
+			"subjects": []any{"a", "b"},
+		}},
+		{Source: "--set env", Values: map[string]any{"env": "prod"}},
+	})
+
+	require.Equal(t, map[string]any{
+		"database": map[string]any{"host": "db", "port": 5432},
+		"cache":    "disabled",
+		"subjects": []any{"a", "b"},
+		"env":      "prod",
+	}, tree)
+	require.Equal(t, map[string]string{
+		"database.host": "prod.yaml",
+		"database.port": "defaults",
+		"cache":         "prod.yaml",
+		"subjects":      "prod.yaml",
+		"env":           "--set env",
+	}, origins)
+}